@@ -0,0 +1,49 @@
+// Command gostk-audit verifies the hash chain of audit files written by the
+// log package, detecting modified or missing records.
+//
+// Usage:
+//
+//    gostk-audit FILE...
+//
+// For each valid file the number of records and the hash of the last record
+// are printed. The exit status is 1 when any file fails the verification.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/registrobr/gostk/log"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: gostk-audit FILE...")
+		os.Exit(2)
+	}
+
+	status := 0
+	for _, filename := range os.Args[1:] {
+		if err := verify(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
+
+func verify(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	records, lastHash, err := log.VerifyAudit(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: ok (%d records, last hash %s)\n", filename, records, lastHash)
+	return nil
+}
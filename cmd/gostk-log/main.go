@@ -0,0 +1,240 @@
+// Command gostk-log reads syslog files, or the standard input, and filters the
+// messages written in the gostk format by the log and errors packages.
+//
+// Usage:
+//
+//    gostk-log [-id ID] [-level LEVEL] [-file FILE] [-since TIME] [-until TIME] [-json] [-f] [FILE...]
+//
+// The level filter keeps the messages with the given priority or higher, using
+// the syslog names (emerg, alert, crit, err, warning, notice, info, debug). The
+// level is only available when the syslog daemon writes the priority in the
+// beginning of the line (<PRI>) or when the messages are in the logfmt format
+// (see log.LogfmtEncoder), so the other lines are discarded by this filter. The
+// file filter matches any location of an error stack. The time filters accept
+// a timestamp (RFC 3339) or a duration relative to now:
+//
+//    gostk-log -since 2h -file gostk/db /var/log/syslog
+//    GOSTK_LOG_FORMAT=logfmt ./server 2>&1 | gostk-log -level err
+//    tail -f /var/log/syslog | gostk-log -id 3f2a9c -json
+//
+// Lines that aren't in the gostk format are ignored.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/registrobr/gostk/log"
+)
+
+// followInterval is the time waited for new content when following a file.
+const followInterval = 500 * time.Millisecond
+
+// filter stores the conditions that a record must satisfy to be printed.
+type filter struct {
+	identifier string
+	level      log.Level
+	file       string
+	since      time.Time
+	until      time.Time
+}
+
+// match checks if the record satisfies all the conditions.
+func (f filter) match(r record) bool {
+	if f.identifier != "" && r.Identifier != f.identifier {
+		return false
+	}
+
+	if f.level >= 0 && (r.priority < 0 || r.priority > f.level) {
+		return false
+	}
+
+	if f.file != "" {
+		found := strings.Contains(r.File, f.file)
+		for _, loc := range r.Stack {
+			found = found || strings.Contains(loc.File, f.file)
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if !f.since.IsZero() && (r.Time == nil || r.Time.Before(f.since)) {
+		return false
+	}
+
+	if !f.until.IsZero() && (r.Time == nil || r.Time.After(f.until)) {
+		return false
+	}
+
+	return true
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "gostk-log:", err)
+		os.Exit(2)
+	}
+}
+
+// run parses the flags and prints the matching records. The errors are
+// returned, so the output is flushed before the program exits.
+func run() error {
+	var (
+		f                   filter
+		level, since, until string
+		jsonOutput, follow  bool
+	)
+
+	flag.StringVar(&f.identifier, "id", "", "show only the messages with the identifier")
+	flag.StringVar(&level, "level", "", "show only the messages with the level or higher (emerg ... debug)")
+	flag.StringVar(&f.file, "file", "", "show only the messages with a location containing the text")
+	flag.StringVar(&since, "since", "", "show only the messages after the time (RFC 3339 or duration)")
+	flag.StringVar(&until, "until", "", "show only the messages before the time (RFC 3339 or duration)")
+	flag.BoolVar(&jsonOutput, "json", false, "print the messages as JSON objects, one per line")
+	flag.BoolVar(&follow, "f", false, "wait for new messages appended to the file")
+	flag.Parse()
+
+	now := time.Now()
+
+	var err error
+	if f.level, err = parseLevel(level); err != nil {
+		return err
+	}
+	if f.since, err = parseTimeFlag(since, now); err != nil {
+		return err
+	}
+	if f.until, err = parseTimeFlag(until, now); err != nil {
+		return err
+	}
+
+	if follow && flag.NArg() != 1 {
+		return errors.New("follow mode requires exactly one file")
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	output := func(line string, r record) error {
+		if !jsonOutput {
+			if _, err := fmt.Fprintln(out, line); err != nil {
+				return err
+			}
+
+		} else {
+			data, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+
+			if _, err = fmt.Fprintf(out, "%s\n", data); err != nil {
+				return err
+			}
+		}
+
+		if follow {
+			// the messages must appear as soon as they are written
+			return out.Flush()
+		}
+		return nil
+	}
+
+	if flag.NArg() == 0 {
+		return scan(os.Stdin, f, false, output)
+	}
+
+	for _, filename := range flag.Args() {
+		file, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+
+		err = scan(file, f, follow, output)
+		file.Close()
+
+		if err != nil {
+			return fmt.Errorf("%s: %s", filename, err)
+		}
+	}
+
+	return out.Flush()
+}
+
+// scan reads the lines of r, sending the records that match the filter to the
+// output function. In follow mode it never returns on the end of the input,
+// waiting for new content.
+func scan(r io.Reader, f filter, follow bool, output func(string, record) error) error {
+	reader := bufio.NewReader(r)
+
+	var pending string
+	for {
+		line, err := reader.ReadString('\n')
+		pending += line
+
+		if err == io.EOF && follow {
+			// wait for the rest of the line
+			time.Sleep(followInterval)
+			continue
+
+		} else if err != nil && err != io.EOF {
+			return err
+		}
+
+		if pending != "" {
+			line, pending = strings.TrimRight(pending, "\r\n"), ""
+
+			if record, ok := parse(line, time.Now()); ok && f.match(record) {
+				if err := output(line, record); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// parseLevel converts the syslog level name. An empty name disables the level
+// filter, returning -1.
+func parseLevel(name string) (log.Level, error) {
+	if name == "" {
+		return -1, nil
+	}
+
+	for level := log.LevelEmergency; level <= log.LevelDebug; level++ {
+		if level.String() == name {
+			return level, nil
+		}
+	}
+
+	return -1, fmt.Errorf("unknown level “%s”", name)
+}
+
+// parseTimeFlag converts a timestamp or a duration before now. An empty value
+// returns the zero time.
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time “%s”", value)
+	}
+
+	return t, nil
+}
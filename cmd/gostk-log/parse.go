@@ -0,0 +1,335 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/registrobr/gostk/log"
+)
+
+// stackSeparator is used by the errors package between the locations of
+// nested errors.
+const stackSeparator = " → "
+
+// errorStackKey precedes the call stack of the error, written by the encoders
+// after the message.
+const errorStackKey = "error.stack="
+
+// logfmtPrefix starts the messages written by the logfmt encoder.
+const logfmtPrefix = "ts="
+
+// timeFormats are the timestamp layouts recognized at the beginning of a line,
+// with the number of space separated tokens used by each one. They cover the
+// syslog traditional and high precision formats and the standard log package
+// format, used by the local fallback.
+var timeFormats = []struct {
+	layout string
+	tokens int
+}{
+	{layout: time.RFC3339Nano, tokens: 1},
+	{layout: "2006/01/02 15:04:05.000000", tokens: 2},
+	{layout: "2006/01/02 15:04:05", tokens: 2},
+	{layout: time.Stamp, tokens: 3},
+}
+
+// location is a file and line reference inside the message.
+type location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// record is a log line in the gostk format, as produced by the text or the
+// logfmt encoders:
+//
+//    [identifier] file:line: message
+//    [identifier] file:line → file:line: message
+//    [identifier] file:line: message error.stack=file:line>file:line
+//    ts=2017-01-02T15:04:05.000Z level=err id=identifier caller=file:line msg=message
+type record struct {
+	Time       *time.Time `json:"time,omitempty"`
+	Host       string     `json:"host,omitempty"`
+	Program    string     `json:"program,omitempty"`
+	Level      string     `json:"level,omitempty"`
+	Identifier string     `json:"id"`
+	File       string     `json:"file,omitempty"`
+	Line       int        `json:"line,omitempty"`
+	Stack      []location `json:"stack,omitempty"`
+	Message    string     `json:"message"`
+
+	// priority stores the level of the record, or -1 when the line doesn't
+	// carry the syslog priority.
+	priority log.Level
+}
+
+// parse extracts the information of a log line. The syslog header is optional
+// and the level is only known when the line starts with the syslog priority
+// (<PRI>) or is in the logfmt format. It returns false when the line isn't in
+// the gostk format.
+func parse(line string, now time.Time) (record, bool) {
+	r := record{priority: -1}
+
+	if strings.HasPrefix(line, "<") {
+		if end := strings.IndexByte(line, '>'); end > 0 {
+			if pri, err := strconv.Atoi(line[1:end]); err == nil && pri >= 0 {
+				r.priority = log.Level(pri % 8)
+				r.Level = r.priority.String()
+				line = line[end+1:]
+
+				// RFC 5424 version
+				line = strings.TrimPrefix(line, "1 ")
+			}
+		}
+	}
+
+	var t time.Time
+	if t, line = parseTime(line, now); !t.IsZero() {
+		r.Time = &t
+	}
+
+	if !strings.HasPrefix(line, "[") && !strings.HasPrefix(line, logfmtPrefix) {
+		// syslog header with the hostname and the program (tag[pid]: )
+		index := headerEnd(line)
+		if index < 0 {
+			return r, false
+		}
+
+		header := strings.Fields(line[:index])
+		if len(header) > 0 {
+			r.Program = header[len(header)-1]
+			if bracket := strings.IndexByte(r.Program, '['); bracket > 0 {
+				r.Program = r.Program[:bracket]
+			}
+		}
+		if len(header) > 1 {
+			r.Host = header[len(header)-2]
+		}
+
+		line = line[index+2:]
+	}
+
+	if strings.HasPrefix(line, logfmtPrefix) {
+		return parseLogfmt(line, r)
+	}
+
+	end := strings.Index(line, "] ")
+	if !strings.HasPrefix(line, "[") || end < 0 {
+		return r, false
+	}
+
+	r.Identifier = line[1:end]
+	r.Message = line[end+2:]
+
+	for {
+		loc, rest, separator, ok := parseLocation(r.Message)
+		if !ok {
+			break
+		}
+
+		r.Stack = append(r.Stack, loc)
+		r.Message = rest
+
+		if separator != stackSeparator {
+			break
+		}
+	}
+
+	if len(r.Stack) > 0 {
+		r.File, r.Line = r.Stack[0].File, r.Stack[0].Line
+	}
+	if len(r.Stack) == 1 {
+		r.Stack = nil
+	}
+
+	// the text encoder writes the call stack of the error as the last item
+	if index := strings.LastIndex(r.Message, " "+errorStackKey); index >= 0 {
+		if stack := parseStack(r.Message[index+len(errorStackKey)+1:]); stack != nil {
+			r.Stack = append(r.Stack, stack...)
+			r.Message = r.Message[:index]
+		}
+	}
+
+	return r, true
+}
+
+// headerEnd returns the position of the separator between the syslog header and
+// a message in one of the gostk formats, or -1 when there's none.
+func headerEnd(line string) int {
+	index := -1
+	for _, separator := range []string{": [", ": " + logfmtPrefix} {
+		if i := strings.Index(line, separator); i >= 0 && (index < 0 || i < index) {
+			index = i
+		}
+	}
+	return index
+}
+
+// parseLogfmt extracts the information of a message written by the logfmt
+// encoder. The timestamp of the message replaces the one of the syslog header,
+// as it is more precise, while the syslog priority has precedence over the
+// level. It returns false when the level or the message are missing.
+func parseLogfmt(line string, r record) (record, bool) {
+	values := logfmtValues(line)
+
+	level, levelOK := values["level"]
+	msg, msgOK := values["msg"]
+	if !levelOK || !msgOK {
+		return r, false
+	}
+
+	if priority, err := parseLevel(level); err == nil && priority >= 0 && r.priority < 0 {
+		r.priority, r.Level = priority, level
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, values["ts"]); err == nil {
+		r.Time = &t
+	}
+
+	if loc, _, _, ok := parseLocation(values["caller"]); ok {
+		r.File, r.Line = loc.File, loc.Line
+	}
+
+	r.Stack = parseStack(values[strings.TrimSuffix(errorStackKey, "=")])
+
+	r.Identifier = values["id"]
+	r.Message = msg
+	return r, true
+}
+
+// parseStack reads the locations of an error call stack, separated by ">". It
+// returns nil when any of them is malformed.
+func parseStack(value string) []location {
+	if value == "" {
+		return nil
+	}
+
+	var stack []location
+	for _, item := range strings.Split(value, ">") {
+		loc, _, _, ok := parseLocation(item)
+		if !ok {
+			return nil
+		}
+		stack = append(stack, loc)
+	}
+	return stack
+}
+
+// logfmtValues reads the key=value pairs of a logfmt line. Quoted values are
+// unescaped, and the parsing stops on the first malformed pair.
+func logfmtValues(line string) map[string]string {
+	values := make(map[string]string)
+
+	for line = strings.TrimLeft(line, " "); line != ""; line = strings.TrimLeft(line, " ") {
+		equal := strings.IndexByte(line, '=')
+		if equal <= 0 {
+			break
+		}
+
+		key := line[:equal]
+		line = line[equal+1:]
+
+		if !strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+
+			values[key], line = line[:end], line[end:]
+			continue
+		}
+
+		end := 1
+		for end < len(line) && line[end] != '"' {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+
+		if end >= len(line) {
+			break
+		}
+
+		value, err := strconv.Unquote(line[:end+1])
+		if err != nil {
+			break
+		}
+
+		values[key], line = value, line[end+1:]
+	}
+
+	return values
+}
+
+// parseTime detects the timestamp in the beginning of the line, returning the
+// remaining of the line. The syslog traditional format doesn't have the year,
+// so the year is chosen to keep the timestamp in the past.
+func parseTime(line string, now time.Time) (time.Time, string) {
+	for _, format := range timeFormats {
+		tokens := strings.SplitN(line, " ", format.tokens+1)
+		if len(tokens) != format.tokens+1 {
+			continue
+		}
+
+		// the day in the syslog format is padded with spaces
+		value := strings.Join(tokens[:format.tokens], " ")
+		rest := tokens[format.tokens]
+		if format.layout == time.Stamp && tokens[1] == "" {
+			tokens = strings.SplitN(line, " ", format.tokens+2)
+			if len(tokens) != format.tokens+2 {
+				continue
+			}
+			value = strings.Join(tokens[:format.tokens+1], " ")
+			rest = tokens[format.tokens+1]
+		}
+
+		t, err := time.ParseInLocation(format.layout, value, now.Location())
+		if err != nil {
+			continue
+		}
+
+		if t.Year() == 0 {
+			t = t.AddDate(now.Year(), 0, 0)
+			if t.After(now.Add(24 * time.Hour)) {
+				t = t.AddDate(-1, 0, 0)
+			}
+		}
+
+		return t, rest
+	}
+
+	return time.Time{}, line
+}
+
+// parseLocation reads a "file:line" reference in the beginning of the text,
+// returning the separator that follows it.
+func parseLocation(text string) (loc location, rest, separator string, ok bool) {
+	colon := strings.IndexByte(text, ':')
+	if colon <= 0 || strings.ContainsAny(text[:colon], " \t") {
+		return
+	}
+
+	digits := colon + 1
+	for digits < len(text) && text[digits] >= '0' && text[digits] <= '9' {
+		digits++
+	}
+
+	line, err := strconv.Atoi(text[colon+1 : digits])
+	if err != nil {
+		return
+	}
+
+	rest = text[digits:]
+	switch {
+	case strings.HasPrefix(rest, stackSeparator):
+		separator = stackSeparator
+	case strings.HasPrefix(rest, ": "):
+		separator = ": "
+	case rest == ":" || rest == "":
+		separator = rest
+	default:
+		return
+	}
+
+	return location{File: text[:colon], Line: line}, rest[len(separator):], separator, true
+}
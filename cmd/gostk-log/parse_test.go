@@ -0,0 +1,209 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/registrobr/gostk/log"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC)
+	timestamp := func(t time.Time) *time.Time { return &t }
+
+	scenarios := []struct {
+		description string
+		line        string
+		expectedOK  bool
+		expected    record
+	}{
+		{
+			description: "it should parse a syslog line correctly",
+			line:        "Jan  2 15:04:00 server1 app[123]: [abc] gostk/log/file.go:42: this is a message",
+			expectedOK:  true,
+			expected: record{
+				Time:       timestamp(time.Date(2017, time.January, 2, 15, 4, 0, 0, time.UTC)),
+				Host:       "server1",
+				Program:    "app",
+				Identifier: "abc",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "this is a message",
+				priority:   -1,
+			},
+		},
+		{
+			description: "it should detect the level and the previous year",
+			line:        "<11>Dec 31 23:59:59 server1 app: [abc] gostk/log/file.go:42: this is a message",
+			expectedOK:  true,
+			expected: record{
+				Time:       timestamp(time.Date(2016, time.December, 31, 23, 59, 59, 0, time.UTC)),
+				Host:       "server1",
+				Program:    "app",
+				Level:      "err",
+				Identifier: "abc",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "this is a message",
+				priority:   log.LevelError,
+			},
+		},
+		{
+			description: "it should parse an error stack",
+			line:        "2017-01-02T15:04:05.123Z server1 app[1]: [] gostk/a/a.go:10 → gostk/b/b.go:20 → gostk/c/c.go:30: low level error",
+			expectedOK:  true,
+			expected: record{
+				Time:    timestamp(time.Date(2017, time.January, 2, 15, 4, 5, 123000000, time.UTC)),
+				Host:    "server1",
+				Program: "app",
+				File:    "gostk/a/a.go",
+				Line:    10,
+				Stack: []location{
+					{File: "gostk/a/a.go", Line: 10},
+					{File: "gostk/b/b.go", Line: 20},
+					{File: "gostk/c/c.go", Line: 30},
+				},
+				Message:  "low level error",
+				priority: -1,
+			},
+		},
+		{
+			description: "it should parse the local fallback format",
+			line:        "2017/01/02 15:04:05 [abc] this is a message: with colon",
+			expectedOK:  true,
+			expected: record{
+				Time:       timestamp(time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC)),
+				Identifier: "abc",
+				Message:    "this is a message: with colon",
+				priority:   -1,
+			},
+		},
+		{
+			description: "it should parse the error stack of the text format",
+			line:        "[abc] gostk/log/file.go:42: failure detected zone=br error.stack=gostk/a/a.go:10>gostk/b/b.go:20",
+			expectedOK:  true,
+			expected: record{
+				Identifier: "abc",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Stack: []location{
+					{File: "gostk/a/a.go", Line: 10},
+					{File: "gostk/b/b.go", Line: 20},
+				},
+				Message:  "failure detected zone=br",
+				priority: -1,
+			},
+		},
+		{
+			description: "it should parse the logfmt format with the level",
+			line:        `Jan  2 15:04:00 server1 app[123]: ts=2017-01-02T15:04:00.123Z level=warning id=abc caller=gostk/log/file.go:42 msg="this is a \"message\"" error.stack=gostk/a/a.go:10>gostk/b/b.go:20 zone=br`,
+			expectedOK:  true,
+			expected: record{
+				Time:       timestamp(time.Date(2017, time.January, 2, 15, 4, 0, 123000000, time.UTC)),
+				Host:       "server1",
+				Program:    "app",
+				Level:      "warning",
+				Identifier: "abc",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Stack: []location{
+					{File: "gostk/a/a.go", Line: 10},
+					{File: "gostk/b/b.go", Line: 20},
+				},
+				Message:  `this is a "message"`,
+				priority: log.LevelWarning,
+			},
+		},
+		{
+			description: "it should parse the logfmt format of the local fallback",
+			line:        "2017/01/02 15:04:05 ts=2017-01-02T15:04:05.000Z level=info msg=message",
+			expectedOK:  true,
+			expected: record{
+				Time:     timestamp(time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC)),
+				Level:    "info",
+				Message:  "message",
+				priority: log.LevelInfo,
+			},
+		},
+		{
+			description: "it should ignore logfmt lines without message",
+			line:        "ts=2017-01-02T15:04:05.000Z level=info",
+		},
+		{
+			description: "it should ignore lines in other formats",
+			line:        "Jan  2 15:04:00 server1 kernel: device eth0 entered promiscuous mode",
+		},
+	}
+
+	for i, scenario := range scenarios {
+		r, ok := parse(scenario.line, now)
+		if ok != scenario.expectedOK {
+			t.Errorf("scenario %d, “%s”: mismatch parse result. Expecting: %t; found %t",
+				i, scenario.description, scenario.expectedOK, ok)
+			continue
+		}
+
+		if ok && !reflect.DeepEqual(r, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%#v”; found “%#v”",
+				i, scenario.description, scenario.expected, r)
+		}
+	}
+}
+
+func TestFilter_match(t *testing.T) {
+	now := time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC)
+	r := record{
+		Time:       &now,
+		Identifier: "abc",
+		File:       "gostk/a/a.go",
+		Stack:      []location{{File: "gostk/a/a.go"}, {File: "gostk/db/db.go"}},
+		priority:   log.LevelWarning,
+	}
+
+	scenarios := []struct {
+		description string
+		filter      filter
+		expected    bool
+	}{
+		{
+			description: "it should match without conditions",
+			filter:      filter{level: -1},
+			expected:    true,
+		},
+		{
+			description: "it should match all the conditions",
+			filter: filter{
+				identifier: "abc",
+				level:      log.LevelNotice,
+				file:       "gostk/db",
+				since:      now.Add(-time.Hour),
+				until:      now.Add(time.Hour),
+			},
+			expected: true,
+		},
+		{
+			description: "it should reject a different identifier",
+			filter:      filter{identifier: "xyz", level: -1},
+		},
+		{
+			description: "it should reject a lower priority",
+			filter:      filter{level: log.LevelError},
+		},
+		{
+			description: "it should reject a different file",
+			filter:      filter{file: "gostk/log", level: -1},
+		},
+		{
+			description: "it should reject a message outside the time window",
+			filter:      filter{since: now.Add(time.Minute), level: -1},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if result := scenario.filter.match(r); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: %t; found %t",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
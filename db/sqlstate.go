@@ -0,0 +1,126 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+)
+
+// ErrorClass groups the database errors that the callers usually handle in a
+// special way, like retrying a transaction after a serialization failure.
+type ErrorClass int
+
+// List of possible database error classes, detected by the SQLSTATE code
+// (https://www.postgresql.org/docs/current/errcodes-appendix.html).
+const (
+	// UnknownError is any error without a known class.
+	UnknownError ErrorClass = iota
+
+	// UniqueViolation is returned when inserting or updating a duplicated
+	// value in a unique constraint (23505).
+	UniqueViolation
+
+	// ForeignKeyViolation is returned when a referenced row doesn't exist or
+	// is still referenced (23503).
+	ForeignKeyViolation
+
+	// SerializationFailure is returned when concurrent transactions can't be
+	// serialized (40001). The transaction can be retried.
+	SerializationFailure
+
+	// DeadlockDetected is returned when the transaction was aborted to break a
+	// deadlock (40P01). The transaction can be retried.
+	DeadlockDetected
+
+	// QueryCanceled is returned when the statement was canceled, usually by
+	// the statement timeout (57014).
+	QueryCanceled
+
+	// ConnectionFailure is returned when the connection with the database was
+	// lost or couldn't be established (class 08 and the server shutdown codes
+	// 57P01, 57P02 and 57P03).
+	ConnectionFailure
+)
+
+// String returns a readable name of the class.
+func (c ErrorClass) String() string {
+	switch c {
+	case UniqueViolation:
+		return "unique violation"
+	case ForeignKeyViolation:
+		return "foreign key violation"
+	case SerializationFailure:
+		return "serialization failure"
+	case DeadlockDetected:
+		return "deadlock detected"
+	case QueryCanceled:
+		return "query canceled"
+	case ConnectionFailure:
+		return "connection failure"
+	}
+	return "unknown error"
+}
+
+// SQLStateError is implemented by the errors of the Postgres drivers that
+// expose the SQLSTATE code, like github.com/lib/pq and github.com/jackc/pgx.
+// Using this interface no specific driver is imported by this library, in the
+// same way of PostgresDriver.
+type SQLStateError interface {
+	error
+	SQLState() string
+}
+
+// SQLState returns the SQLSTATE code of the first error in the chain that
+// implements SQLStateError, or an empty string when there's no code.
+func SQLState(err error) string {
+	var stateErr SQLStateError
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState()
+	}
+	return ""
+}
+
+// Classify detects the class of a database error using its SQLSTATE code. The
+// errors wrapped by the gostk errors package, or by fmt.Errorf with %w, are
+// also classified:
+//
+//    _, err := tx.Exec("INSERT INTO domain (fqdn) VALUES ($1)", fqdn)
+//    if db.Classify(err) == db.UniqueViolation {
+//      return ErrDomainAlreadyExists
+//    }
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return UnknownError
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, ErrUnreachable) {
+		return ConnectionFailure
+	}
+
+	switch state := SQLState(err); {
+	case state == "23505":
+		return UniqueViolation
+	case state == "23503":
+		return ForeignKeyViolation
+	case state == "40001":
+		return SerializationFailure
+	case state == "40P01":
+		return DeadlockDetected
+	case state == "57014":
+		return QueryCanceled
+	case strings.HasPrefix(state, "08"), state == "57P01", state == "57P02", state == "57P03":
+		return ConnectionFailure
+	}
+
+	return UnknownError
+}
+
+// Retryable checks if the transaction that returned the error can be
+// executed again, as the failure was caused by concurrent transactions.
+func Retryable(err error) bool {
+	switch Classify(err) {
+	case SerializationFailure, DeadlockDetected:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package db_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/registrobr/gostk/db"
+	"github.com/registrobr/gostk/errors"
+)
+
+func TestClassify(t *testing.T) {
+	scenarios := []struct {
+		description       string
+		err               error
+		expected          db.ErrorClass
+		expectedRetryable bool
+	}{
+		{
+			description: "it should detect a unique violation",
+			err:         postgresError{code: "23505"},
+			expected:    db.UniqueViolation,
+		},
+		{
+			description: "it should detect a foreign key violation wrapped by the errors package",
+			err:         errors.Wrap(errors.New(postgresError{code: "23503"}), "inserting the contact"),
+			expected:    db.ForeignKeyViolation,
+		},
+		{
+			description:       "it should detect a serialization failure wrapped by the standard library",
+			err:               fmt.Errorf("committing: %w", postgresError{code: "40001"}),
+			expected:          db.SerializationFailure,
+			expectedRetryable: true,
+		},
+		{
+			description:       "it should detect a deadlock",
+			err:               postgresError{code: "40P01"},
+			expected:          db.DeadlockDetected,
+			expectedRetryable: true,
+		},
+		{
+			description: "it should detect a canceled query",
+			err:         postgresError{code: "57014"},
+			expected:    db.QueryCanceled,
+		},
+		{
+			description: "it should detect a connection failure by the SQLSTATE class",
+			err:         postgresError{code: "08006"},
+			expected:    db.ConnectionFailure,
+		},
+		{
+			description: "it should detect a connection failure of the driver",
+			err:         errors.New(driver.ErrBadConn),
+			expected:    db.ConnectionFailure,
+		},
+		{
+			description: "it should detect an unreachable database",
+			err:         db.ErrUnreachable,
+			expected:    db.ConnectionFailure,
+		},
+		{
+			description: "it should not classify other SQLSTATE codes",
+			err:         postgresError{code: "42P01"},
+			expected:    db.UnknownError,
+		},
+		{
+			description: "it should not classify errors without SQLSTATE",
+			err:         fmt.Errorf("generic error"),
+			expected:    db.UnknownError,
+		},
+		{
+			description: "it should not classify a nil error",
+			expected:    db.UnknownError,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if class := db.Classify(scenario.err); class != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expected, class)
+		}
+
+		if retryable := db.Retryable(scenario.err); retryable != scenario.expectedRetryable {
+			t.Errorf("scenario %d, “%s”: mismatch retryable. Expecting: %t; found %t",
+				i, scenario.description, scenario.expectedRetryable, retryable)
+		}
+	}
+}
+
+func TestSQLState(t *testing.T) {
+	err := errors.Wrap(postgresError{code: "23505"}, "inserting the domain")
+	if state := db.SQLState(err); state != "23505" {
+		t.Errorf("mismatch results. Expecting: “23505”; found “%s”", state)
+	}
+
+	if state := db.SQLState(fmt.Errorf("generic error")); state != "" {
+		t.Errorf("unexpected SQLSTATE “%s”", state)
+	}
+}
+
+// postgresError simulates the error of a Postgres driver, like *pq.Error.
+type postgresError struct {
+	code string
+}
+
+func (e postgresError) Error() string {
+	return "pq: error " + e.code
+}
+
+func (e postgresError) SQLState() string {
+	return e.code
+}
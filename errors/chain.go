@@ -0,0 +1,37 @@
+package errors
+
+import (
+	stderrors "errors"
+)
+
+// Is reports whether any error in the chain of err matches target, including
+// the low level errors encapsulated by this package. It works exactly as the
+// standard errors.Is, and is here for convenience:
+//
+//    if errors.Is(err, sql.ErrNoRows) {
+//      return nil
+//    }
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in the chain of err that matches target, and if
+// so, sets target to that error value and returns true. It works exactly as
+// the standard errors.As, and is here for convenience.
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+// Cause returns the root cause of the error, walking the chain of wrapped
+// errors until the last one. A nil error returns nil.
+func Cause(err error) error {
+	for err != nil {
+		next := stderrors.Unwrap(err)
+		if next == nil {
+			break
+		}
+		err = next
+	}
+
+	return err
+}
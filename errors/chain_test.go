@@ -0,0 +1,113 @@
+package errors_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/registrobr/gostk/errors"
+)
+
+func TestIs(t *testing.T) {
+	scenarios := []struct {
+		description string
+		err         error
+		target      error
+		expected    bool
+	}{
+		{
+			description: "it should find an encapsulated error",
+			err:         errors.New(sql.ErrNoRows),
+			target:      sql.ErrNoRows,
+			expected:    true,
+		},
+		{
+			description: "it should find an error in many levels of encapsulation",
+			err:         errors.New(fmt.Errorf("query failed: %w", errors.New(errors.New(sql.ErrNoRows)))),
+			target:      sql.ErrNoRows,
+			expected:    true,
+		},
+		{
+			description: "it should find an error wrapped by the formatting constructors",
+			err:         errors.Critf("connection failed: %w", errors.New(sql.ErrConnDone)),
+			target:      sql.ErrConnDone,
+			expected:    true,
+		},
+		{
+			description: "it should detect a different error",
+			err:         errors.New(errors.New(sql.ErrNoRows)),
+			target:      sql.ErrTxDone,
+		},
+		{
+			description: "it should detect a nil error",
+			target:      sql.ErrNoRows,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if result := errors.Is(scenario.err, scenario.target); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: %t; found %t",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestAs(t *testing.T) {
+	_, lowLevelErr := os.Open("/this/file/does/not/exist")
+	err := errors.New(fmt.Errorf("loading configuration: %w", errors.New(lowLevelErr)))
+
+	var pathErr *os.PathError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("error “%v” not found in “%v”", lowLevelErr, err)
+	}
+
+	if pathErr.Path != "/this/file/does/not/exist" {
+		t.Errorf("mismatch results. Expecting: “/this/file/does/not/exist”; found “%s”", pathErr.Path)
+	}
+
+	var numErr *customError
+	if errors.As(err, &numErr) {
+		t.Errorf("unexpected error found “%v”", numErr)
+	}
+}
+
+type customError struct{}
+
+func (*customError) Error() string {
+	return "custom error"
+}
+
+func TestCause(t *testing.T) {
+	scenarios := []struct {
+		description string
+		err         error
+		expected    error
+	}{
+		{
+			description: "it should return the low level error",
+			err:         errors.New(sql.ErrNoRows),
+			expected:    sql.ErrNoRows,
+		},
+		{
+			description: "it should walk many levels of encapsulation",
+			err:         errors.New(fmt.Errorf("query failed: %w", errors.New(errors.New(sql.ErrNoRows)))),
+			expected:    sql.ErrNoRows,
+		},
+		{
+			description: "it should keep an error without encapsulation",
+			err:         sql.ErrNoRows,
+			expected:    sql.ErrNoRows,
+		},
+		{
+			description: "it should ignore a nil error",
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if result := errors.Cause(scenario.err); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/registrobr/gostk/log"
+	"github.com/registrobr/gostk/runtime"
+)
+
+// Category groups the error kinds by the type of problem, useful to decide how
+// the error is reported to the clients.
+type Category string
+
+// List of possible categories of an error kind.
+const (
+	// CategoryValidation indicates invalid data sent by the client.
+	CategoryValidation Category = "validation"
+
+	// CategoryNotFound indicates that a requested resource doesn't exist.
+	CategoryNotFound Category = "not-found"
+
+	// CategoryConflict indicates that the request conflicts with the current
+	// state of a resource.
+	CategoryConflict Category = "conflict"
+
+	// CategoryInternal indicates an unexpected failure in the system.
+	CategoryInternal Category = "internal"
+
+	// CategoryUnavailable indicates that a dependency is temporarily
+	// unavailable, so the operation can be retried later.
+	CategoryUnavailable Category = "unavailable"
+)
+
+// kinds stores all the defined error kinds by code.
+var kinds = struct {
+	sync.RWMutex
+	codes map[string]*Kind
+}{
+	codes: make(map[string]*Kind),
+}
+
+// Kind is a type of error identified by a stable and machine-readable code,
+// independent of the message text. Kinds are usually defined as package
+// variables:
+//
+//    var ErrDomainNotFound = errors.Define("DOMAIN_NOT_FOUND",
+//      errors.CategoryNotFound, log.LevelNotice, "domain %s not found")
+//
+//    func load(fqdn string) (Domain, error) {
+//      ...
+//      if err == sql.ErrNoRows {
+//        return Domain{}, ErrDomainNotFound.Wrap(err, fqdn)
+//      }
+//    }
+//
+// The errors of a kind can be detected with errors.Is(err, ErrDomainNotFound)
+// or with the Code function.
+type Kind struct {
+	// Code identifies the kind, like "DOMAIN_NOT_FOUND".
+	Code string
+
+	// Category is the type of problem.
+	Category Category
+
+	// Level is the default log level of the errors.
+	Level log.Level
+
+	// Message is the template of the error message, formatted with the
+	// arguments given when creating the errors.
+	Message string
+}
+
+// Define registers a new error kind. It panics if the code is empty or already
+// defined, as it is a programming error detected during the initialization.
+func Define(code string, category Category, level log.Level, message string) *Kind {
+	if code == "" {
+		panic("errors: empty error code")
+	}
+
+	kinds.Lock()
+	defer kinds.Unlock()
+
+	if _, exists := kinds.codes[code]; exists {
+		panic("errors: error code " + code + " defined twice")
+	}
+
+	kind := &Kind{
+		Code:     code,
+		Category: category,
+		Level:    level,
+		Message:  message,
+	}
+
+	kinds.codes[code] = kind
+	return kind
+}
+
+// Lookup returns the error kind with the code, or nil when it isn't defined.
+func Lookup(code string) *Kind {
+	kinds.RLock()
+	defer kinds.RUnlock()
+	return kinds.codes[code]
+}
+
+// Kinds returns all the defined error kinds ordered by code. Useful to
+// document the errors of an API.
+func Kinds() []*Kind {
+	kinds.RLock()
+	defer kinds.RUnlock()
+
+	list := make([]*Kind, 0, len(kinds.codes))
+	for _, kind := range kinds.codes {
+		list = append(list, kind)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Code < list[j].Code
+	})
+	return list
+}
+
+// Error returns the code, so the kind can be used as the target of errors.Is.
+func (k *Kind) Error() string {
+	return k.Code
+}
+
+// New returns an error of the kind, formatting the message template with the
+// arguments and storing the file and line location.
+func (k *Kind) New(a ...interface{}) error {
+	file, line, _ := runtime.Caller(1)
+	err := codedError{kind: k, msg: fmt.Sprintf(k.Message, a...)}
+	return traceableError{err: err, file: file, line: line, level: k.Level, stack: capture()}
+}
+
+// Wrap returns an error of the kind that encapsulates another error, like a
+// low level database error, that is still reachable with errors.Is and
+// errors.As. If err is nil, Wrap returns nil.
+func (k *Kind) Wrap(err error, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	file, line, _ := runtime.Caller(1)
+	err = codedError{kind: k, msg: fmt.Sprintf(k.Message, a...), err: err}
+	return traceableError{err: err, file: file, line: line, level: k.Level, stack: capture()}
+}
+
+// codedError stores the kind of the error with the formatted message.
+type codedError struct {
+	kind *Kind
+	msg  string
+	err  error
+}
+
+// Error returns the message, followed by the encapsulated error, if any.
+func (e codedError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+// Unwrap returns the encapsulated error.
+func (e codedError) Unwrap() error {
+	return e.err
+}
+
+// Is detects the kind of the error.
+func (e codedError) Is(target error) bool {
+	kind, ok := target.(*Kind)
+	return ok && kind == e.kind
+}
+
+// KindOf returns the kind of the first coded error in the chain, or nil when
+// there's no coded error. Aggregates, as MultiError, are searched in the order
+// of their members.
+func KindOf(err error) *Kind {
+	var coded codedError
+	if stderrors.As(err, &coded) {
+		return coded.kind
+	}
+	return nil
+}
+
+// Message returns the message of the first coded error in the chain, without
+// the locations and the encapsulated errors, so it is safe to show to the
+// clients. An empty string is returned when there's no coded error.
+func Message(err error) string {
+	var coded codedError
+	if stderrors.As(err, &coded) {
+		return coded.msg
+	}
+	return ""
+}
+
+// Code returns the code of the first coded error in the chain, or an empty
+// string when there's no coded error.
+func Code(err error) string {
+	if kind := KindOf(err); kind != nil {
+		return kind.Code
+	}
+	return ""
+}
+
+// CategoryOf returns the category of the first coded error in the chain. Errors
+// without code are considered internal failures. A nil error returns an empty
+// category.
+func CategoryOf(err error) Category {
+	if err == nil {
+		return ""
+	}
+
+	if kind := KindOf(err); kind != nil {
+		return kind.Category
+	}
+	return CategoryInternal
+}
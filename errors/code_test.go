@@ -0,0 +1,144 @@
+package errors_test
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/registrobr/gostk/errors"
+	"github.com/registrobr/gostk/log"
+)
+
+var (
+	errDomainNotFound = errors.Define("TEST_DOMAIN_NOT_FOUND", errors.CategoryNotFound, log.LevelNotice, "domain %s not found")
+	errInvalidContact = errors.Define("TEST_INVALID_CONTACT", errors.CategoryValidation, log.LevelInfo, "invalid contact")
+)
+
+func TestKind(t *testing.T) {
+	type leveler interface {
+		Level() log.Level
+	}
+
+	scenarios := []struct {
+		description      string
+		err              error
+		expected         *regexp.Regexp
+		expectedCode     string
+		expectedCategory errors.Category
+		expectedLevel    log.Level
+	}{
+		{
+			description:      "it should create an error of the kind",
+			err:              errDomainNotFound.New("example.com.br"),
+			expected:         regexp.MustCompile("^gostk/errors/code_test.go:[0-9]+: domain example.com.br not found$"),
+			expectedCode:     "TEST_DOMAIN_NOT_FOUND",
+			expectedCategory: errors.CategoryNotFound,
+			expectedLevel:    log.LevelNotice,
+		},
+		{
+			description: "it should detect the kind in a wrapped error",
+			err:         errors.Wrap(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "loading the domain"),
+			expected: regexp.MustCompile("^gostk/errors/code_test.go:[0-9]+: loading the domain → " +
+				"gostk/errors/code_test.go:[0-9]+: domain example.com.br not found: sql: no rows in result set$"),
+			expectedCode:     "TEST_DOMAIN_NOT_FOUND",
+			expectedCategory: errors.CategoryNotFound,
+			expectedLevel:    log.LevelNotice,
+		},
+		{
+			description:      "it should detect the kind wrapped by the standard library",
+			err:              fmt.Errorf("checking contacts: %w", errInvalidContact.New()),
+			expected:         regexp.MustCompile("^checking contacts: gostk/errors/code_test.go:[0-9]+: invalid contact$"),
+			expectedCode:     "TEST_INVALID_CONTACT",
+			expectedCategory: errors.CategoryValidation,
+			expectedLevel:    log.LevelError,
+		},
+		{
+			description: "it should detect the kind of the first coded member of an aggregate",
+			err:         newMultiError(errors.Errorf("timeout"), errDomainNotFound.New("a.br"), errInvalidContact.New()),
+			expected: regexp.MustCompile("^3 errors occurred:\n" +
+				"1\\. gostk/errors/code_test.go:[0-9]+: timeout\n" +
+				"2\\. gostk/errors/code_test.go:[0-9]+: domain a.br not found\n" +
+				"3\\. gostk/errors/code_test.go:[0-9]+: invalid contact$"),
+			expectedCode:     "TEST_DOMAIN_NOT_FOUND",
+			expectedCategory: errors.CategoryNotFound,
+			expectedLevel:    log.LevelError,
+		},
+		{
+			description:      "it should consider an error without code as internal",
+			err:              errors.Critf("database failure"),
+			expected:         regexp.MustCompile("^gostk/errors/code_test.go:[0-9]+: database failure$"),
+			expectedCategory: errors.CategoryInternal,
+			expectedLevel:    log.LevelCritical,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if !scenario.expected.MatchString(scenario.err.Error()) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected.String(), scenario.err)
+		}
+
+		if code := errors.Code(scenario.err); code != scenario.expectedCode {
+			t.Errorf("scenario %d, “%s”: mismatch codes. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expectedCode, code)
+		}
+
+		if category := errors.CategoryOf(scenario.err); category != scenario.expectedCategory {
+			t.Errorf("scenario %d, “%s”: mismatch categories. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expectedCategory, category)
+		}
+
+		level := log.LevelError
+		if l, ok := scenario.err.(leveler); ok {
+			level = l.Level()
+		}
+
+		if level != scenario.expectedLevel {
+			t.Errorf("scenario %d, “%s”: mismatch levels. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedLevel, level)
+		}
+	}
+}
+
+func TestKind_Is(t *testing.T) {
+	err := errors.Wrap(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "loading the domain")
+
+	if !errors.Is(err, errDomainNotFound) {
+		t.Errorf("kind not detected in “%v”", err)
+	}
+
+	if errors.Is(err, errInvalidContact) {
+		t.Errorf("unexpected kind detected in “%v”", err)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("low level error not detected in “%v”", err)
+	}
+
+	if msg := errors.Message(err); msg != "domain example.com.br not found" {
+		t.Errorf("mismatch message. Expecting: “domain example.com.br not found”; found “%s”", msg)
+	}
+
+	if err := errDomainNotFound.Wrap(nil); err != nil {
+		t.Errorf("unexpected error “%v”", err)
+	}
+}
+
+func TestDefine(t *testing.T) {
+	if kind := errors.Lookup("TEST_DOMAIN_NOT_FOUND"); kind != errDomainNotFound {
+		t.Errorf("mismatch kind. Expecting: “%v”; found “%v”", errDomainNotFound, kind)
+	}
+
+	if kind := errors.Lookup("TEST_UNKNOWN"); kind != nil {
+		t.Errorf("unexpected kind “%v”", kind)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("duplicated code not detected")
+		}
+	}()
+
+	errors.Define("TEST_DOMAIN_NOT_FOUND", errors.CategoryInternal, log.LevelError, "other")
+}
@@ -2,7 +2,8 @@
 package errors
 
 // Equal compares the errors messages. This is useful in unit tests to compare
-// encapsulated error messages.
+// encapsulated error messages, ignoring the locations. The messages added with
+// Wrap are also compared.
 func Equal(first, second error) bool {
 	if first == nil || second == nil {
 		return first == second
@@ -13,7 +14,7 @@ func Equal(first, second error) bool {
 
 	if ok1 {
 		if ok2 {
-			return Equal(err1.err, err2.err)
+			return err1.msg == err2.msg && Equal(err1.err, err2.err)
 		}
 
 		return Equal(err1.err, second)
@@ -38,6 +38,18 @@ func TestEqual(t *testing.T) {
 			err2:        errors.Errorf("this is a test"),
 			expected:    true,
 		},
+		{
+			description: "it should compare correctly 2 equal wrapped errors",
+			err1:        errors.Wrap(errors.Errorf("this is a test"), "doing something"),
+			err2:        errors.Wrap(fmt.Errorf("this is a test"), "doing something"),
+			expected:    true,
+		},
+		{
+			description: "it should detect when 2 wrapped errors have different messages",
+			err1:        errors.Wrap(errors.Errorf("this is a test"), "doing something"),
+			err2:        errors.Wrap(errors.Errorf("this is a test"), "doing other thing"),
+			expected:    false,
+		},
 		{
 			description: "it should detect when errors are different (1)",
 			err1:        errors.Errorf("this is a test 1"),
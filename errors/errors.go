@@ -3,51 +3,116 @@ package errors
 
 import (
 	"fmt"
-	"runtime"
+	"io"
 
 	"github.com/registrobr/gostk/log"
 	"github.com/registrobr/gostk/path"
+	"github.com/registrobr/gostk/runtime"
 )
 
 // pathDeep defines the number of directories that are visible when printing an
 // error location.
 const pathDeep = 3
 
+// CaptureStack enables the capture of the full call stack when creating the
+// errors, besides the location. The stack is printed with the %+v verb and sent
+// to the structured log encoders, but it has a cost, so it is disabled by
+// default. It should be defined only during the initialization. To capture the
+// stack of specific errors use WithStack instead.
+var CaptureStack = false
+
 // traceableError stores the low level error with the location.
 type traceableError struct {
 	err   error
+	msg   string
 	file  string
 	line  int
 	level log.Level
+
+	// fields and stack are pointers to keep the error comparable.
+	fields *log.Fields
+	stack  *runtime.Stack
 }
 
 // New returns an error that encapsulates another low level error, storing the
-// file and line location.
+// file and line location. Functions marked with runtime.Helper are skipped when
+// retrieving the location.
 func New(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	_, file, line, _ := runtime.Caller(1)
-	return traceableError{err: err, file: file, line: line, level: log.LevelError}
+	file, line, _ := runtime.Caller(1)
+	return traceableError{err: err, file: file, line: line, level: log.LevelError, stack: capture()}
+}
+
+// WithStack works exactly as New but always captures the full call stack,
+// even when CaptureStack is disabled.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	file, line, _ := runtime.Caller(1)
+	stack := runtime.Callers(1)
+	return traceableError{err: err, file: file, line: line, level: log.LevelError, stack: &stack}
+}
+
+// Wrap returns an error that adds a message describing what was being done to
+// another error, storing the file and line location. The message is written
+// before the encapsulated error, creating a readable chain:
+//
+//    gostk/config/config.go:42: loading configuration → gostk/config/file.go:10: open config.json: no such file or directory
+//
+// The log level of the encapsulated error is preserved. If err is nil, Wrap
+// returns nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	file, line, _ := runtime.Caller(1)
+	return traceableError{err: err, msg: msg, file: file, line: line, level: levelOf(err), stack: capture()}
+}
+
+// Wrapf works exactly as Wrap, formatting the message with the arguments.
+func Wrapf(err error, msg string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	file, line, _ := runtime.Caller(1)
+	return traceableError{err: err, msg: fmt.Sprintf(msg, a...), file: file, line: line, level: levelOf(err), stack: capture()}
 }
 
 // NewWithFollowUp works exactly as New but defines the number of invocations to
 // follow-up to retrieve the actual caller of the error. Useful when the user
 // adds an extra layer over the current Error type.
+//
+// Deprecated: mark the extra layer with runtime.Helper and use New instead.
 func NewWithFollowUp(err error, followUp int) error {
 	if err == nil {
 		return nil
 	}
 
-	_, file, line, _ := runtime.Caller(followUp)
-	return traceableError{err: err, file: file, line: line, level: log.LevelError}
+	file, line, _ := runtime.Caller(followUp)
+	return traceableError{err: err, file: file, line: line, level: log.LevelError, stack: capture()}
 }
 
 // Error string representation of the error adding the location.
 func (e traceableError) Error() string {
+	_, traceable := e.err.(traceableError)
+
+	if e.msg != "" {
+		format := "%s:%d: %s: %s"
+		if traceable {
+			format = "%s:%d: %s → %s"
+		}
+		return fmt.Sprintf(format, path.RelevantPath(e.file, pathDeep), e.line, e.msg, e.err.Error())
+	}
+
 	format := "%s:%d: %s"
-	if _, ok := e.err.(traceableError); ok {
+	if traceable {
 		// stacktrace format
 		format = "%s:%d → %s"
 	}
@@ -55,6 +120,42 @@ func (e traceableError) Error() string {
 	return fmt.Sprintf(format, path.RelevantPath(e.file, pathDeep), e.line, e.err.Error())
 }
 
+// Format implements fmt.Formatter. The verb %+v prints the full call stack,
+// when it was captured, after the error message. The other verbs format the
+// error message as a string.
+func (e traceableError) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') {
+		io.WriteString(f, e.Error())
+		for _, frame := range e.Frames() {
+			fmt.Fprintf(f, "\n%s\n\t%s:%d", frame.Function, path.RelevantPath(frame.File, pathDeep), frame.Line)
+		}
+		return
+	}
+
+	fmt.Fprintf(f, fmt.FormatString(f, verb), e.Error())
+}
+
+// Frames returns the call stack captured when the error was created, or nil
+// when the capture was disabled.
+func (e traceableError) Frames() []runtime.Frame {
+	if e.stack == nil {
+		return nil
+	}
+	return e.stack.Frames()
+}
+
+// Unwrap returns the encapsulated error, so the standard library functions
+// errors.Is and errors.As can walk the chain.
+func (e traceableError) Unwrap() error {
+	return e.err
+}
+
+// Location returns the file, with only the relevant directories, and the line
+// where the error was created.
+func (e traceableError) Location() (file string, line int) {
+	return path.RelevantPath(e.file, pathDeep), e.line
+}
+
 // Level returns the priority of this error. Useful when logging with this
 // library in a syslog server.
 func (e traceableError) Level() log.Level {
@@ -64,31 +165,101 @@ func (e traceableError) Level() log.Level {
 // Emergf returns an error that formats as the given text with an emergency log
 // level.
 func Emergf(msg string, a ...interface{}) error {
-	_, file, line, _ := runtime.Caller(1)
+	file, line, _ := runtime.Caller(1)
 	err := fmt.Errorf(msg, a...)
-	return traceableError{err: err, file: file, line: line, level: log.LevelEmergency}
+	return traceableError{err: err, file: file, line: line, level: log.LevelEmergency, stack: capture()}
 }
 
 // Alertf returns an error that formats as the given text with an emergency log
 // level.
 func Alertf(msg string, a ...interface{}) error {
-	_, file, line, _ := runtime.Caller(1)
+	file, line, _ := runtime.Caller(1)
 	err := fmt.Errorf(msg, a...)
-	return traceableError{err: err, file: file, line: line, level: log.LevelAlert}
+	return traceableError{err: err, file: file, line: line, level: log.LevelAlert, stack: capture()}
 }
 
 // Critf returns an error that formats as the given text with an emergency log
 // level.
 func Critf(msg string, a ...interface{}) error {
-	_, file, line, _ := runtime.Caller(1)
+	file, line, _ := runtime.Caller(1)
 	err := fmt.Errorf(msg, a...)
-	return traceableError{err: err, file: file, line: line, level: log.LevelCritical}
+	return traceableError{err: err, file: file, line: line, level: log.LevelCritical, stack: capture()}
 }
 
 // Errorf returns an error that formats as the given text with an error log
 // level.
 func Errorf(msg string, a ...interface{}) error {
-	_, file, line, _ := runtime.Caller(1)
+	file, line, _ := runtime.Caller(1)
+	err := fmt.Errorf(msg, a...)
+	return traceableError{err: err, file: file, line: line, level: log.LevelError, stack: capture()}
+}
+
+// Warningf returns an error that formats as the given text with a warning log
+// level.
+func Warningf(msg string, a ...interface{}) error {
+	file, line, _ := runtime.Caller(1)
+	err := fmt.Errorf(msg, a...)
+	return traceableError{err: err, file: file, line: line, level: log.LevelWarning, stack: capture()}
+}
+
+// Noticef returns an error that formats as the given text with a notice log
+// level.
+func Noticef(msg string, a ...interface{}) error {
+	file, line, _ := runtime.Caller(1)
+	err := fmt.Errorf(msg, a...)
+	return traceableError{err: err, file: file, line: line, level: log.LevelNotice, stack: capture()}
+}
+
+// Infof returns an error that formats as the given text with an informational log
+// level.
+func Infof(msg string, a ...interface{}) error {
+	file, line, _ := runtime.Caller(1)
+	err := fmt.Errorf(msg, a...)
+	return traceableError{err: err, file: file, line: line, level: log.LevelInfo, stack: capture()}
+}
+
+// Debugf returns an error that formats as the given text with a debug log
+// level.
+func Debugf(msg string, a ...interface{}) error {
+	file, line, _ := runtime.Caller(1)
 	err := fmt.Errorf(msg, a...)
-	return traceableError{err: err, file: file, line: line, level: log.LevelError}
+	return traceableError{err: err, file: file, line: line, level: log.LevelDebug, stack: capture()}
+}
+
+// WithLevel changes the log level of an error. The location and the stack of
+// an error created by this package are preserved, while other errors are
+// encapsulated like New, storing the location of the caller.
+func WithLevel(err error, level log.Level) error {
+	if err == nil {
+		return nil
+	}
+
+	if traceable, ok := err.(traceableError); ok {
+		traceable.level = level
+		return traceable
+	}
+
+	file, line, _ := runtime.Caller(1)
+	return traceableError{err: err, file: file, line: line, level: level, stack: capture()}
+}
+
+// capture returns the call stack of the error constructor's caller when
+// CaptureStack is enabled.
+func capture() *runtime.Stack {
+	if !CaptureStack {
+		return nil
+	}
+
+	// skip capture and the error constructor
+	stack := runtime.Callers(2)
+	return &stack
+}
+
+// levelOf returns the log level of the error, or the error level when it isn't
+// defined.
+func levelOf(err error) log.Level {
+	if levelError, ok := err.(interface{ Level() log.Level }); ok {
+		return levelError.Level()
+	}
+	return log.LevelError
 }
@@ -1,12 +1,18 @@
 package errors_test
 
 import (
+	"bytes"
+	"database/sql"
 	"fmt"
+	stdlog "log"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/registrobr/gostk/errors"
 	"github.com/registrobr/gostk/log"
+	"github.com/registrobr/gostk/runtime"
 )
 
 func TestNew(t *testing.T) {
@@ -35,6 +41,21 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_helper(t *testing.T) {
+	_, line, _ := runtime.Caller(0)
+	err := helperNew(fmt.Errorf("this is a test"))
+
+	expected := regexp.MustCompile("^gostk/errors/errors_test.go:" + strconv.Itoa(line+1) + ": this is a test$")
+	if result := err.Error(); !expected.MatchString(result) {
+		t.Errorf("mismatch results. Expecting: “%v”; found “%v”", expected.String(), result)
+	}
+}
+
+func helperNew(err error) error {
+	runtime.Helper()
+	return errors.New(err)
+}
+
 func TestNewWithFollowUp(t *testing.T) {
 	scenarios := []struct {
 		description   string
@@ -68,6 +89,64 @@ func TestNewWithFollowUp(t *testing.T) {
 	}
 }
 
+func TestWrap(t *testing.T) {
+	type leveler interface {
+		Level() log.Level
+	}
+
+	scenarios := []struct {
+		description   string
+		err           error
+		expected      *regexp.Regexp
+		expectedLevel log.Level
+	}{
+		{
+			description:   "it should add a message to a low level error",
+			err:           errors.Wrap(sql.ErrNoRows, "loading the domain"),
+			expected:      regexp.MustCompile("^gostk/errors/errors_test.go:[0-9]+: loading the domain: sql: no rows in result set$"),
+			expectedLevel: log.LevelError,
+		},
+		{
+			description: "it should create a readable chain with many levels",
+			err: errors.Wrapf(
+				errors.Wrap(errors.Critf("connection refused"), "querying the database"),
+				"loading the domain %s", "example.com.br",
+			),
+			expected: regexp.MustCompile("^gostk/errors/errors_test.go:[0-9]+: loading the domain example.com.br → " +
+				"gostk/errors/errors_test.go:[0-9]+: querying the database → " +
+				"gostk/errors/errors_test.go:[0-9]+: connection refused$"),
+			expectedLevel: log.LevelCritical,
+		},
+		{
+			description:   "it should keep the stack format of errors without message",
+			err:           errors.Wrap(errors.New(sql.ErrNoRows), "loading the domain"),
+			expected:      regexp.MustCompile("^gostk/errors/errors_test.go:[0-9]+: loading the domain → gostk/errors/errors_test.go:[0-9]+: sql: no rows in result set$"),
+			expectedLevel: log.LevelError,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if !scenario.expected.MatchString(scenario.err.Error()) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected.String(), scenario.err)
+		}
+
+		if level := scenario.err.(leveler).Level(); level != scenario.expectedLevel {
+			t.Errorf("scenario %d, “%s”: mismatch levels. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedLevel, level)
+		}
+	}
+
+	err := errors.Wrap(errors.Wrapf(sql.ErrNoRows, "query %d", 1), "loading the domain")
+	if !errors.Is(err, sql.ErrNoRows) || errors.Cause(err) != sql.ErrNoRows {
+		t.Errorf("innermost error not reachable in “%v”", err)
+	}
+
+	if err := errors.Wrap(nil, "loading the domain"); err != nil {
+		t.Errorf("unexpected error “%v”", err)
+	}
+}
+
 func TestTraceableError_Error(t *testing.T) {
 	scenarios := []struct {
 		description string
@@ -126,6 +205,26 @@ func TestTraceableError_Level(t *testing.T) {
 			err:         errors.Errorf("this is a test").(leveler),
 			expected:    log.LevelError,
 		},
+		{
+			description: "it should set a warning level",
+			err:         errors.Warningf("this is a test").(leveler),
+			expected:    log.LevelWarning,
+		},
+		{
+			description: "it should set a notice level",
+			err:         errors.Noticef("this is a test").(leveler),
+			expected:    log.LevelNotice,
+		},
+		{
+			description: "it should set an informational level",
+			err:         errors.Infof("this is a test").(leveler),
+			expected:    log.LevelInfo,
+		},
+		{
+			description: "it should set a debug level",
+			err:         errors.Debugf("this is a test").(leveler),
+			expected:    log.LevelDebug,
+		},
 	}
 
 	for i, scenario := range scenarios {
@@ -135,3 +234,177 @@ func TestTraceableError_Level(t *testing.T) {
 		}
 	}
 }
+
+func TestTraceableError_Location(t *testing.T) {
+	type locator interface {
+		Location() (string, int)
+	}
+
+	err := errors.Errorf("this is a test")
+	_, expectedLine, _ := runtime.Caller(0)
+	expectedLine--
+
+	file, line := err.(locator).Location()
+	if file != "gostk/errors/errors_test.go" || line != expectedLine {
+		t.Errorf("mismatch results. Expecting: “gostk/errors/errors_test.go:%d”; found “%s:%d”", expectedLine, file, line)
+	}
+
+	var buf bytes.Buffer
+	core := log.NewCore()
+	core.SetLocalLogger(stdlog.New(&buf, "", 0))
+	core.SetLocalEncoder(log.LogfmtEncoder{})
+	core.NewLogger("test").Error(err)
+
+	expected := fmt.Sprintf("error.type=errors.traceableError error.chain=errors.traceableError>*errors.errorString "+
+		"error.file=gostk/errors/errors_test.go error.line=%d error.level=err\n", expectedLine)
+	if result := buf.String(); !strings.HasSuffix(result, expected) {
+		t.Errorf("mismatch log. Expecting suffix: “%s”; found “%s”", expected, result)
+	}
+}
+
+func TestTraceableError_Format(t *testing.T) {
+	scenarios := []struct {
+		description string
+		err         func() error
+		format      string
+		expected    *regexp.Regexp
+	}{
+		{
+			description: "it should print only the message without the stack",
+			err: func() error {
+				return errors.Errorf("this is a test")
+			},
+			format:   "%+v",
+			expected: regexp.MustCompile(`^gostk/errors/errors_test.go:[0-9]+: this is a test$`),
+		},
+		{
+			description: "it should print the stack captured explicitly",
+			err: func() error {
+				return errors.WithStack(fmt.Errorf("this is a test"))
+			},
+			format: "%+v",
+			expected: regexp.MustCompile(`^gostk/errors/errors_test.go:[0-9]+: this is a test\n` +
+				`github.com/registrobr/gostk/errors_test.TestTraceableError_Format.func2\n\tgostk/errors/errors_test.go:[0-9]+\n` +
+				`github.com/registrobr/gostk/errors_test.TestTraceableError_Format\n\tgostk/errors/errors_test.go:[0-9]+\n`),
+		},
+		{
+			description: "it should print the stack captured globally",
+			err: func() error {
+				errors.CaptureStack = true
+				defer func() { errors.CaptureStack = false }()
+				return errors.Critf("this is a test")
+			},
+			format: "%+v",
+			expected: regexp.MustCompile(`^gostk/errors/errors_test.go:[0-9]+: this is a test\n` +
+				`github.com/registrobr/gostk/errors_test.TestTraceableError_Format.func3\n\tgostk/errors/errors_test.go:[0-9]+\n`),
+		},
+		{
+			description: "it should not print the stack in the simple format",
+			err: func() error {
+				return errors.WithStack(fmt.Errorf("this is a test"))
+			},
+			format:   "%v",
+			expected: regexp.MustCompile(`^gostk/errors/errors_test.go:[0-9]+: this is a test$`),
+		},
+		{
+			description: "it should quote the message",
+			err: func() error {
+				return errors.Wrap(fmt.Errorf("test"), "a")
+			},
+			format:   "%q",
+			expected: regexp.MustCompile(`^"gostk/errors/errors_test.go:[0-9]+: a: test"$`),
+		},
+		{
+			description: "it should format the message with the other string verbs",
+			err: func() error {
+				return errors.Wrap(fmt.Errorf("test"), "a")
+			},
+			format:   "%x",
+			expected: regexp.MustCompile(`^[0-9a-f]+3a20613a2074657374$`),
+		},
+		{
+			description: "it should keep the width and flags",
+			err: func() error {
+				return errors.Wrap(fmt.Errorf("test"), "a")
+			},
+			format:   "[%-50s]",
+			expected: regexp.MustCompile(`^\[gostk/errors/errors_test.go:[0-9]+: a: test +\]$`),
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if result := fmt.Sprintf(scenario.format, scenario.err()); !scenario.expected.MatchString(result) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected.String(), result)
+		}
+	}
+}
+
+func TestTraceableError_Frames(t *testing.T) {
+	type framer interface {
+		Frames() []runtime.Frame
+	}
+
+	if frames := errors.Errorf("this is a test").(framer).Frames(); frames != nil {
+		t.Errorf("unexpected frames “%v”", frames)
+	}
+
+	frames := helperWithStack(fmt.Errorf("this is a test")).(framer).Frames()
+	if len(frames) == 0 || frames[0].Function != "github.com/registrobr/gostk/errors_test.TestTraceableError_Frames" {
+		t.Errorf("helper function not skipped: “%v”", frames)
+	}
+}
+
+func helperWithStack(err error) error {
+	runtime.Helper()
+	return errors.WithStack(err)
+}
+
+func TestWithLevel(t *testing.T) {
+	type leveler interface {
+		Level() log.Level
+	}
+
+	original := errors.Errorf("this is a test")
+
+	scenarios := []struct {
+		description   string
+		err           error
+		level         log.Level
+		expected      log.Level
+		expectedError *regexp.Regexp
+	}{
+		{
+			description:   "it should keep the original location",
+			err:           original,
+			level:         log.LevelWarning,
+			expected:      log.LevelWarning,
+			expectedError: regexp.MustCompile("^" + regexp.QuoteMeta(original.Error()) + "$"),
+		},
+		{
+			description:   "it should encapsulate a low level error",
+			err:           fmt.Errorf("this is a test"),
+			level:         log.LevelCritical,
+			expected:      log.LevelCritical,
+			expectedError: regexp.MustCompile("^gostk/errors/errors_test.go:[0-9]+: this is a test$"),
+		},
+	}
+
+	for i, scenario := range scenarios {
+		err := errors.WithLevel(scenario.err, scenario.level)
+
+		if level := err.(leveler).Level(); level != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch levels. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected, level)
+		}
+
+		if !scenario.expectedError.MatchString(err.Error()) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedError.String(), err)
+		}
+	}
+
+	if err := errors.WithLevel(nil, log.LevelDebug); err != nil {
+		t.Errorf("unexpected error “%v”", err)
+	}
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	stderrors "errors"
+
+	"github.com/registrobr/gostk/log"
+	"github.com/registrobr/gostk/runtime"
+)
+
+// WithField attaches a key/value pair to the error, so it is logged as a
+// structured field instead of being formatted into the message:
+//
+//    return errors.WithField(err, "domain", domain)
+//
+// The location and the stack of an error created by this package are
+// preserved, while other errors are encapsulated like New, storing the
+// location of the caller.
+func WithField(err error, key string, value interface{}) error {
+	runtime.Helper()
+	return WithFields(err, log.Fields{key: value})
+}
+
+// WithFields attaches many key/value pairs to the error. Check WithField for
+// more details.
+func WithFields(err error, fields log.Fields) error {
+	if err == nil {
+		return nil
+	}
+
+	traceable, ok := err.(traceableError)
+	if !ok {
+		file, line, _ := runtime.Caller(1)
+		traceable = traceableError{err: err, file: file, line: line, level: log.LevelError, stack: capture()}
+	}
+
+	var current log.Fields
+	if traceable.fields != nil {
+		current = *traceable.fields
+	}
+
+	merged := make(log.Fields, len(current)+len(fields))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	traceable.fields = &merged
+	return traceable
+}
+
+// Fields returns the fields attached to the error and to the errors in its
+// chain, where the outer errors replace the fields with the same key.
+func (e traceableError) Fields() log.Fields {
+	var fields log.Fields
+	for current := e.err; current != nil; current = stderrors.Unwrap(current) {
+		if inner, ok := current.(interface{ Fields() log.Fields }); ok {
+			fields = inner.Fields()
+			break
+		}
+	}
+
+	if e.fields == nil {
+		return fields
+	}
+
+	merged := make(log.Fields, len(fields)+len(*e.fields))
+	for key, value := range fields {
+		merged[key] = value
+	}
+	for key, value := range *e.fields {
+		merged[key] = value
+	}
+	return merged
+}
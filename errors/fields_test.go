@@ -0,0 +1,87 @@
+package errors_test
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	stdlog "log"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/registrobr/gostk/errors"
+	"github.com/registrobr/gostk/log"
+)
+
+func TestWithFields(t *testing.T) {
+	type fielder interface {
+		Fields() log.Fields
+	}
+
+	scenarios := []struct {
+		description string
+		err         error
+		expected    log.Fields
+	}{
+		{
+			description: "it should attach a field to a low level error",
+			err:         errors.WithField(sql.ErrNoRows, "domain", "example.com.br"),
+			expected:    log.Fields{"domain": "example.com.br"},
+		},
+		{
+			description: "it should merge the fields of the same error",
+			err: errors.WithFields(
+				errors.WithField(errors.Errorf("this is a test"), "domain", "example.com.br"),
+				log.Fields{"contact": "ABC123"},
+			),
+			expected: log.Fields{"domain": "example.com.br", "contact": "ABC123"},
+		},
+		{
+			description: "it should merge the fields through the wrap chain",
+			err: errors.WithFields(
+				errors.Wrap(
+					errors.WithFields(errors.Errorf("this is a test"), log.Fields{"domain": "example.com.br", "attempt": 1}),
+					"loading the domain",
+				),
+				log.Fields{"attempt": 2},
+			),
+			expected: log.Fields{"domain": "example.com.br", "attempt": 2},
+		},
+		{
+			description: "it should merge the fields of wrapped low level errors",
+			err: errors.Wrap(
+				fmt.Errorf("checking: %w", errors.WithField(sql.ErrNoRows, "domain", "example.com.br")),
+				"loading the domain",
+			),
+			expected: log.Fields{"domain": "example.com.br"},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if fields := scenario.err.(fielder).Fields(); !reflect.DeepEqual(fields, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%#v”; found “%#v”",
+				i, scenario.description, scenario.expected, fields)
+		}
+	}
+
+	if err := errors.WithField(nil, "domain", "example.com.br"); err != nil {
+		t.Errorf("unexpected error “%v”", err)
+	}
+}
+
+func TestWithFields_log(t *testing.T) {
+	var buf bytes.Buffer
+	core := log.NewCore()
+	core.SetLocalLogger(stdlog.New(&buf, "", 0))
+	core.SetLocalEncoder(log.TextEncoder{})
+
+	err := errors.Wrap(errors.WithField(sql.ErrNoRows, "domain", "example.com.br"), "loading the domain")
+	core.NewLogger("test").(log.FieldLogger).WithFields(log.Fields{"request": "abc"}).Error(err)
+
+	expected := regexp.MustCompile(`^\[test\] gostk/errors/fields_test.go:[0-9]+: loading the domain → ` +
+		`gostk/errors/fields_test.go:[0-9]+: sql: no rows in result set domain=example.com.br request=abc\n$`)
+
+	if result := buf.String(); !expected.MatchString(result) {
+		t.Errorf("mismatch results. Expecting: “%s”; found “%s”", expected.String(), result)
+	}
+}
@@ -0,0 +1,223 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/registrobr/gostk/log"
+	"github.com/registrobr/gostk/path"
+)
+
+// jsonError is the JSON representation of each error in the chain. The errors
+// created by this package have the level and the location, the coded errors
+// have the code and the category, and the MultiError has the collected errors.
+type jsonError struct {
+	Message  string     `json:"message"`
+	Context  string     `json:"context,omitempty"`
+	Level    string     `json:"level,omitempty"`
+	File     string     `json:"file,omitempty"`
+	Line     int        `json:"line,omitempty"`
+	Code     string     `json:"code,omitempty"`
+	Category Category   `json:"category,omitempty"`
+	Fields   log.Fields `json:"fields,omitempty"`
+	Cause    *jsonError   `json:"cause,omitempty"`
+	Errors   []*jsonError `json:"errors,omitempty"`
+}
+
+// MarshalJSON returns the JSON representation of the error with its cause
+// chain, useful to send errors between services:
+//
+//    {
+//      "message": "gostk/domain/load.go:42: loading the domain → ...",
+//      "context": "loading the domain",
+//      "level": "notice",
+//      "file": "gostk/domain/load.go",
+//      "line": 42,
+//      "fields": {"domain": "example.com.br"},
+//      "cause": { ... }
+//    }
+//
+// The call stack isn't sent. Use UnmarshalError to reconstruct the error.
+func (e traceableError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONError(e))
+}
+
+// MarshalJSON returns a JSON array with the representation of each collected
+// error (see the MarshalJSON of the errors created by this package), so a
+// batch of errors can be sent to other services. Use UnmarshalError to
+// reconstruct the MultiError.
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONErrors(m.Errors()))
+}
+
+func newJSONErrors(errs []error) []*jsonError {
+	items := make([]*jsonError, len(errs))
+	for i, err := range errs {
+		items[i] = newJSONError(err)
+	}
+	return items
+}
+
+func newJSONError(err error) *jsonError {
+	switch e := err.(type) {
+	case traceableError:
+		j := &jsonError{
+			Message: e.Error(),
+			Context: e.msg,
+			Level:   e.level.String(),
+			File:    path.RelevantPath(e.file, pathDeep),
+			Line:    e.line,
+			Cause:   newJSONError(e.err),
+		}
+		if e.fields != nil {
+			j.Fields = *e.fields
+		}
+		return j
+
+	case codedError:
+		j := &jsonError{
+			Message:  e.msg,
+			Code:     e.kind.Code,
+			Category: e.kind.Category,
+		}
+		if e.err != nil {
+			j.Cause = newJSONError(e.err)
+		}
+		return j
+
+	case *MultiError:
+		return &jsonError{Message: e.Error(), Errors: newJSONErrors(e.Errors())}
+	}
+
+	// other wrappers (e.g. fmt.Errorf with %w) may have coded errors in the
+	// chain, so the cause is also encoded
+	j := &jsonError{Message: err.Error()}
+	if cause := stderrors.Unwrap(err); cause != nil {
+		j.Cause = newJSONError(cause)
+	}
+	return j
+}
+
+// wrappedError rebuilds the errors from other packages that encapsulate a
+// cause. The message already contains the message of the cause.
+type wrappedError struct {
+	msg string
+	err error
+}
+
+// Error returns the original message.
+func (e wrappedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the encapsulated error.
+func (e wrappedError) Unwrap() error {
+	return e.err
+}
+
+// UnmarshalError reconstructs an error from its JSON representation (see
+// MarshalJSON), storing it in target. The levels, locations, codes and fields
+// are kept, so the error can be compared with Equal and logged like the
+// original one. The low level errors are rebuilt only with their messages,
+// keeping the chain of the wrappers. Codes that aren't defined in this process
+// are rebuilt with the category sent. An error is returned when the data is
+// invalid:
+//
+//    var remoteErr error
+//    if err := errors.UnmarshalError(data, &remoteErr); err != nil {
+//      return err
+//    }
+func UnmarshalError(data []byte, target *error) error {
+	var j jsonError
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		// JSON array of a MultiError
+		if err := json.Unmarshal(data, &j.Errors); err != nil {
+			return err
+		}
+		if j.Errors == nil {
+			j.Errors = []*jsonError{}
+		}
+
+	} else if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	err, parseErr := j.toError()
+	if parseErr != nil {
+		return parseErr
+	}
+
+	*target = err
+	return nil
+}
+
+// toError rebuilds the error of the JSON representation, returning an error
+// when the data is invalid.
+func (j *jsonError) toError() (error, error) {
+	var cause error
+	if j.Cause != nil {
+		var err error
+		if cause, err = j.Cause.toError(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case j.Errors != nil:
+		m := new(MultiError)
+		for _, item := range j.Errors {
+			if item == nil {
+				return nil, fmt.Errorf("missing error in “%s”", j.Message)
+			}
+
+			err, parseErr := item.toError()
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			m.Append(err)
+		}
+		return m, nil
+
+	case j.Level != "":
+		level, err := parseLevel(j.Level)
+		if err != nil {
+			return nil, err
+		}
+
+		if cause == nil {
+			return nil, fmt.Errorf("missing cause of error “%s”", j.Message)
+		}
+
+		e := traceableError{err: cause, msg: j.Context, file: j.File, line: j.Line, level: level}
+		if j.Fields != nil {
+			fields := j.Fields
+			e.fields = &fields
+		}
+		return e, nil
+
+	case j.Code != "":
+		kind := Lookup(j.Code)
+		if kind == nil {
+			kind = &Kind{Code: j.Code, Category: j.Category, Level: log.LevelError}
+		}
+		return codedError{kind: kind, msg: j.Message, err: cause}, nil
+
+	case cause != nil:
+		return wrappedError{msg: j.Message, err: cause}, nil
+	}
+
+	return stderrors.New(j.Message), nil
+}
+
+// parseLevel converts the syslog name of a log level.
+func parseLevel(name string) (log.Level, error) {
+	for level := log.LevelEmergency; level <= log.LevelDebug; level++ {
+		if level.String() == name {
+			return level, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown level “%s”", name)
+}
@@ -0,0 +1,220 @@
+package errors_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	stdlog "log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/registrobr/gostk/errors"
+	"github.com/registrobr/gostk/log"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	scenarios := []struct {
+		description string
+		err         error
+	}{
+		{
+			description: "it should encode a simple error",
+			err:         errors.Warningf("this is a test"),
+		},
+		{
+			description: "it should encode a chain of wrapped errors",
+			err: errors.WithField(
+				errors.Wrap(errors.New(sql.ErrNoRows), "loading the domain"),
+				"domain", "example.com.br",
+			),
+		},
+		{
+			description: "it should encode a coded error",
+			err:         errors.Wrap(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "loading the domain"),
+		},
+		{
+			description: "it should encode a chain with a wrapper from other package",
+			err: errors.Wrap(
+				fmt.Errorf("checking the owner: %w", errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br")),
+				"loading the domain",
+			),
+		},
+		{
+			description: "it should encode all the errors of a MultiError",
+			err: newMultiError(
+				errors.Wrap(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "loading the domain"),
+				errors.Critf("connection refused"),
+			),
+		},
+		{
+			description: "it should encode a wrapped MultiError",
+			err: errors.Wrap(newMultiError(
+				errDomainNotFound.New("example.com.br"),
+				errors.Noticef("invalid contact"),
+			), "checking the domains"),
+		},
+	}
+
+	for i, scenario := range scenarios {
+		data, err := json.Marshal(scenario.err)
+		if err != nil {
+			t.Errorf("scenario %d, “%s”: unexpected error: %s", i, scenario.description, err)
+			continue
+		}
+
+		var decoded error
+		if err := errors.UnmarshalError(data, &decoded); err != nil {
+			t.Errorf("scenario %d, “%s”: unexpected error: %s", i, scenario.description, err)
+			continue
+		}
+
+		if decoded.Error() != scenario.err.Error() || !errors.Equal(decoded, scenario.err) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.err, decoded)
+		}
+
+		if errors.Is(scenario.err, errDomainNotFound) && !errors.Is(decoded, errDomainNotFound) {
+			t.Errorf("scenario %d, “%s”: kind not found in the decoded error", i, scenario.description)
+		}
+
+		if errors.Code(decoded) != errors.Code(scenario.err) {
+			t.Errorf("scenario %d, “%s”: mismatch codes. Expecting: “%s”; found “%s”",
+				i, scenario.description, errors.Code(scenario.err), errors.Code(decoded))
+		}
+
+		type leveler interface {
+			Level() log.Level
+		}
+
+		if expected, result := scenario.err.(leveler).Level(), decoded.(leveler).Level(); expected != result {
+			t.Errorf("scenario %d, “%s”: mismatch levels. Expecting: “%v”; found “%v”",
+				i, scenario.description, expected, result)
+		}
+	}
+}
+
+func TestMarshalJSON_format(t *testing.T) {
+	err := errors.WithField(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "attempt", 2)
+
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("unexpected error: %s", marshalErr)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cause, _ := result["cause"].(map[string]interface{})
+	if cause == nil {
+		t.Fatalf("cause not found in “%s”", data)
+	}
+	delete(result, "cause")
+	delete(result, "line")
+
+	expected := map[string]interface{}{
+		"message": err.Error(),
+		"level":   "notice",
+		"file":    "gostk/errors/json_test.go",
+		"fields":  map[string]interface{}{"attempt": float64(2)},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("mismatch results. Expecting: “%#v”; found “%#v”", expected, result)
+	}
+
+	expectedCause := map[string]interface{}{
+		"message":  "domain example.com.br not found",
+		"code":     "TEST_DOMAIN_NOT_FOUND",
+		"category": "not-found",
+		"cause":    map[string]interface{}{"message": "sql: no rows in result set"},
+	}
+
+	if !reflect.DeepEqual(cause, expectedCause) {
+		t.Errorf("mismatch cause. Expecting: “%#v”; found “%#v”", expectedCause, cause)
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	data := []byte(`{"message":"","context":"sending the notification","level":"crit","file":"gostk/notify/notify.go","line":42,` +
+		`"fields":{"queue":"mail"},"cause":{"message":"quota exceeded","code":"REMOTE_QUOTA_EXCEEDED","category":"unavailable"}}`)
+
+	var err error
+	if decodeErr := errors.UnmarshalError(data, &err); decodeErr != nil {
+		t.Fatalf("unexpected error: %s", decodeErr)
+	}
+
+	expected := "gostk/notify/notify.go:42: sending the notification: quota exceeded"
+	if err.Error() != expected {
+		t.Errorf("mismatch results. Expecting: “%s”; found “%s”", expected, err)
+	}
+
+	if category := errors.CategoryOf(err); category != errors.CategoryUnavailable {
+		t.Errorf("mismatch categories. Expecting: “unavailable”; found “%s”", category)
+	}
+
+	var buf bytes.Buffer
+	core := log.NewCore()
+	core.SetLocalLogger(stdlog.New(&buf, "", 0))
+	core.SetLocalEncoder(log.LogfmtEncoder{})
+	core.NewLogger("test").Error(err)
+
+	for _, item := range []string{"level=crit", "queue=mail", "error.file=gostk/notify/notify.go", "error.line=42"} {
+		if !strings.Contains(buf.String(), item) {
+			t.Errorf("“%s” not found in the log “%s”", item, buf.String())
+		}
+	}
+
+	for _, invalid := range []string{`{"level":"unknown","cause":{"message":"x"}}`, `{"level":"err"}`, `[`} {
+		var decoded error
+		if err := errors.UnmarshalError([]byte(invalid), &decoded); err == nil {
+			t.Errorf("error not detected decoding “%s”", invalid)
+		}
+	}
+
+	var simple error
+	if err := errors.UnmarshalError([]byte(`{"message":"simple error"}`), &simple); err != nil || !errors.Equal(simple, fmt.Errorf("simple error")) {
+		t.Errorf("mismatch results. Expecting: “simple error”; found “%v” (%v)", simple, err)
+	}
+}
+
+func TestMultiError_MarshalJSON(t *testing.T) {
+	m := newMultiError(errDomainNotFound.New("example.com.br"), fmt.Errorf("connection refused"))
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil || len(items) != 2 {
+		t.Fatalf("expected a JSON array with 2 errors; found “%s”", data)
+	}
+
+	if expected := map[string]interface{}{"message": "connection refused"}; !reflect.DeepEqual(items[1], expected) {
+		t.Errorf("mismatch results. Expecting: “%#v”; found “%#v”", expected, items[1])
+	}
+
+	var decoded error
+	if err := errors.UnmarshalError(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	multi, ok := decoded.(*errors.MultiError)
+	if !ok || multi.Len() != 2 || decoded.Error() != m.Error() {
+		t.Fatalf("mismatch results. Expecting: “%v”; found “%v”", m, decoded)
+	}
+
+	if !errors.Is(decoded, errDomainNotFound) {
+		t.Error("kind not found in the decoded errors")
+	}
+}
+
+func newMultiError(errs ...error) error {
+	var m errors.MultiError
+	m.Append(errs...)
+	return &m
+}
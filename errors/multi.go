@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+
+	"github.com/registrobr/gostk/log"
+)
+
+// MultiError collects many errors, useful for batch operations that should
+// report all failures together. The zero value is ready to use and it can be
+// appended simultaneously from multiple goroutines:
+//
+//    var errs errors.MultiError
+//    for _, contact := range contacts {
+//      if err := validate(contact); err != nil {
+//        errs.Append(errors.Wrapf(err, "validating contact %s", contact.Handle))
+//      }
+//    }
+//    return errs.ErrorOrNil()
+//
+// The standard errors.Is and errors.As functions check all the collected
+// errors.
+type MultiError struct {
+	mutex  sync.RWMutex
+	errors []error
+}
+
+// Append adds the errors to the collection, ignoring the nil ones.
+func (m *MultiError) Append(errs ...error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, err := range errs {
+		if err != nil {
+			m.errors = append(m.errors, err)
+		}
+	}
+}
+
+// Len returns the number of errors collected.
+func (m *MultiError) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.errors)
+}
+
+// Errors returns a copy of the collected errors in the order they were
+// appended.
+func (m *MultiError) Errors() []error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]error(nil), m.errors...)
+}
+
+// ErrorOrNil returns nil when no error was collected, avoiding a non-nil error
+// interface with an empty collection.
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+// Error formats the collected errors as a numbered list.
+func (m *MultiError) Error() string {
+	errs := m.Errors()
+
+	var buf bytes.Buffer
+	buf.WriteString(strconv.Itoa(len(errs)))
+	if len(errs) == 1 {
+		buf.WriteString(" error occurred:")
+	} else {
+		buf.WriteString(" errors occurred:")
+	}
+
+	for i, err := range errs {
+		buf.WriteByte('\n')
+		buf.WriteString(strconv.Itoa(i + 1))
+		buf.WriteString(". ")
+		buf.WriteString(err.Error())
+	}
+
+	return buf.String()
+}
+
+// Level returns the highest priority of the collected errors, so the log
+// package reports the most severe problem. The error level is used for errors
+// without priority.
+func (m *MultiError) Level() log.Level {
+	errs := m.Errors()
+	if len(errs) == 0 {
+		return log.LevelError
+	}
+
+	level := log.LevelDebug
+	for _, err := range errs {
+		if errLevel := levelOf(err); errLevel < level {
+			level = errLevel
+		}
+	}
+	return level
+}
+
+// Unwrap returns the collected errors, so the standard library functions
+// errors.Is and errors.As can check all of them.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors()
+}
@@ -0,0 +1,117 @@
+package errors_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/registrobr/gostk/errors"
+	"github.com/registrobr/gostk/log"
+)
+
+func TestMultiError(t *testing.T) {
+	scenarios := []struct {
+		description   string
+		errs          []error
+		expected      *regexp.Regexp
+		expectedLevel log.Level
+	}{
+		{
+			description:   "it should format a single error",
+			errs:          []error{errors.Warningf("invalid e-mail")},
+			expected:      regexp.MustCompile("^1 error occurred:\n1. gostk/errors/multi_test.go:[0-9]+: invalid e-mail$"),
+			expectedLevel: log.LevelWarning,
+		},
+		{
+			description: "it should format the errors as a numbered list with the highest severity",
+			errs: []error{
+				errors.Warningf("invalid e-mail"),
+				nil,
+				errors.Critf("database failure"),
+				fmt.Errorf("invalid phone"),
+			},
+			expected: regexp.MustCompile("^3 errors occurred:\n" +
+				"1. gostk/errors/multi_test.go:[0-9]+: invalid e-mail\n" +
+				"2. gostk/errors/multi_test.go:[0-9]+: database failure\n" +
+				"3. invalid phone$"),
+			expectedLevel: log.LevelCritical,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var errs errors.MultiError
+		errs.Append(scenario.errs...)
+
+		if !scenario.expected.MatchString(errs.Error()) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected.String(), errs.Error())
+		}
+
+		if level := errs.Level(); level != scenario.expectedLevel {
+			t.Errorf("scenario %d, “%s”: mismatch levels. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedLevel, level)
+		}
+	}
+}
+
+func TestMultiError_ErrorOrNil(t *testing.T) {
+	var errs errors.MultiError
+	if err := errs.ErrorOrNil(); err != nil {
+		t.Errorf("unexpected error “%v”", err)
+	}
+
+	errs.Append(nil)
+	if err := errs.ErrorOrNil(); err != nil {
+		t.Errorf("unexpected error “%v”", err)
+	}
+
+	errs.Append(fmt.Errorf("this is a test"))
+	if err := errs.ErrorOrNil(); err == nil {
+		t.Error("error not returned")
+	}
+}
+
+func TestMultiError_IsAs(t *testing.T) {
+	_, lowLevelErr := os.Open("/this/file/does/not/exist")
+
+	var errs errors.MultiError
+	errs.Append(errors.Errorf("invalid e-mail"), errors.Wrap(sql.ErrNoRows, "loading contact"))
+	errs.Append(errors.New(lowLevelErr))
+
+	err := errors.Wrap(errs.ErrorOrNil(), "validating contacts")
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("error “%v” not found in “%v”", sql.ErrNoRows, err)
+	}
+
+	if errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("unexpected error “%v” found in “%v”", sql.ErrTxDone, err)
+	}
+
+	var pathErr *os.PathError
+	if !errors.As(err, &pathErr) {
+		t.Errorf("error “%v” not found in “%v”", lowLevelErr, err)
+	}
+}
+
+func TestMultiError_concurrency(t *testing.T) {
+	var errs errors.MultiError
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs.Append(fmt.Errorf("error %d", i))
+			_ = errs.Error()
+		}(i)
+	}
+	wg.Wait()
+
+	if n := errs.Len(); n != 50 {
+		t.Errorf("mismatch number of errors. Expecting: 50; found %d", n)
+	}
+}
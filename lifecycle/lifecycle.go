@@ -0,0 +1,143 @@
+// Package lifecycle handles the graceful shutdown of a process, stopping its
+// components in order when a SIGINT or SIGTERM signal is received.
+//
+// Components register shutdown hooks while they are started, and the hooks are
+// executed in the reverse order of the registration, like deferred functions.
+// The log destinations are always closed at the end, so the other components
+// can still log while stopping:
+//
+//    db := db.NewDB(conn, 3*time.Second)
+//    lifecycle.Register("database", lifecycle.Closer(db))
+//
+//    server := &http.Server{Addr: ":8080"}
+//    lifecycle.Register("http server", server.Shutdown)
+//    go server.ListenAndServe()
+//
+//    if err := lifecycle.Wait(10 * time.Second); err != nil {
+//      os.Exit(1)
+//    }
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/registrobr/gostk/log"
+)
+
+// Hook stops a component. It should return as soon as possible when the
+// context is done.
+type Hook func(ctx context.Context) error
+
+// Closer adapts an io.Closer, as db.DB or a log sink, to a shutdown hook.
+func Closer(c io.Closer) Hook {
+	return func(ctx context.Context) error {
+		return c.Close()
+	}
+}
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Manager stores the shutdown hooks of a process.
+type Manager struct {
+	mutex sync.Mutex
+	hooks []namedHook
+}
+
+// NewManager returns a Manager without hooks. Most processes should use the
+// package functions, that work over a default Manager.
+func NewManager() *Manager {
+	return new(Manager)
+}
+
+// Register adds a shutdown hook identified by name, used when reporting
+// failures.
+func (m *Manager) Register(name string, hook Hook) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.hooks = append(m.hooks, namedHook{name: name, hook: hook})
+}
+
+// Shutdown executes the hooks in the reverse order of the registration and then
+// flushes and closes the log destinations. Hook failures are logged and don't
+// interrupt the shutdown, but if the context is done the remaining hooks are
+// skipped. The log destinations are closed even then, so the buffered entries
+// aren't lost. It returns the first error found.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mutex.Lock()
+	hooks := m.hooks
+	m.hooks = nil
+	m.mutex.Unlock()
+
+	var err error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if ctx.Err() != nil {
+			log.Errorf("shutdown of “%s” skipped: %s", hooks[i].name, ctx.Err())
+			if err == nil {
+				err = ctx.Err()
+			}
+			continue
+		}
+
+		if hookErr := hooks[i].hook(ctx); hookErr != nil {
+			log.Errorf("shutdown of “%s” failed: %s", hooks[i].name, hookErr)
+			if err == nil {
+				err = hookErr
+			}
+		}
+	}
+
+	if logErr := log.Shutdown(ctx); logErr != nil && err == nil {
+		err = logErr
+	}
+
+	return err
+}
+
+// Wait blocks until the process receives a SIGINT or SIGTERM signal and then
+// executes the shutdown, that must finish before the timeout.
+func (m *Manager) Wait(timeout time.Duration) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	return m.wait(signals, timeout)
+}
+
+func (m *Manager) wait(signals <-chan os.Signal, timeout time.Duration) error {
+	sig := <-signals
+	log.Noticef("signal “%s” received, shutting down", sig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return m.Shutdown(ctx)
+}
+
+// defaultManager is used by the package functions.
+var defaultManager = NewManager()
+
+// Register adds a shutdown hook to the default Manager.
+func Register(name string, hook Hook) {
+	defaultManager.Register(name, hook)
+}
+
+// Shutdown executes the hooks of the default Manager.
+func Shutdown(ctx context.Context) error {
+	return defaultManager.Shutdown(ctx)
+}
+
+// Wait blocks until a SIGINT or SIGTERM signal and executes the hooks of the
+// default Manager.
+func Wait(timeout time.Duration) error {
+	return defaultManager.Wait(timeout)
+}
@@ -0,0 +1,120 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/registrobr/gostk/log"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	var calls []string
+	hook := func(name string, err error) Hook {
+		return func(ctx context.Context) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	scenarios := []struct {
+		description   string
+		hooks         []string
+		failures      map[string]error
+		timeout       time.Duration
+		expectedCalls []string
+		expectedError error
+	}{
+		{
+			description:   "it should execute the hooks in the reverse order",
+			hooks:         []string{"database", "http server"},
+			timeout:       time.Second,
+			expectedCalls: []string{"http server", "database", "log"},
+		},
+		{
+			description: "it should continue after a hook failure",
+			hooks:       []string{"database", "http server"},
+			failures: map[string]error{
+				"http server": fmt.Errorf("generic error"),
+			},
+			timeout:       time.Second,
+			expectedCalls: []string{"http server", "database", "log"},
+			expectedError: fmt.Errorf("generic error"),
+		},
+		{
+			description:   "it should skip the hooks when the deadline is exceeded",
+			hooks:         []string{"database", "http server"},
+			timeout:       -time.Second,
+			expectedCalls: []string{"log"},
+			expectedError: context.DeadlineExceeded,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		calls = nil
+		log.AddSink("log", mockSink{closed: func() {
+			calls = append(calls, "log")
+		}})
+
+		m := NewManager()
+		for _, name := range scenario.hooks {
+			m.Register(name, hook(name, scenario.failures[name]))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+		err := m.Shutdown(ctx)
+		cancel()
+
+		if !reflect.DeepEqual(err, scenario.expectedError) {
+			t.Errorf("scenario %d, “%s”: mismatch errors. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedError, err)
+		}
+
+		if !reflect.DeepEqual(calls, scenario.expectedCalls) {
+			t.Errorf("scenario %d, “%s”: mismatch calls. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedCalls, calls)
+		}
+
+		log.RemoveSink("log")
+	}
+}
+
+func TestManager_wait(t *testing.T) {
+	stopped := make(chan bool, 1)
+
+	m := NewManager()
+	m.Register("server", func(ctx context.Context) error {
+		stopped <- true
+		return nil
+	})
+
+	signals := make(chan os.Signal, 1)
+	signals <- syscall.SIGTERM
+
+	if err := m.wait(signals, time.Second); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("shutdown hook not executed")
+	}
+}
+
+type mockSink struct {
+	closed func()
+}
+
+func (m mockSink) Write(e log.Entry) error {
+	return nil
+}
+
+func (m mockSink) Close() error {
+	m.closed()
+	return nil
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAuditModified returned when the content of an audit record doesn't
+	// match its hash.
+	ErrAuditModified = errors.New("audit record modified")
+
+	// ErrAuditGap returned when an audit record is missing, detected by a gap
+	// in the sequence numbers.
+	ErrAuditGap = errors.New("audit sequence gap")
+
+	// ErrAuditBrokenChain returned when an audit record doesn't reference the
+	// hash of the previous record.
+	ErrAuditBrokenChain = errors.New("audit hash chain broken")
+
+	// ErrAuditMalformed returned when an audit line can't be parsed.
+	ErrAuditMalformed = errors.New("malformed audit record")
+)
+
+// AuditError stores the line of the audit file where a problem was detected.
+type AuditError struct {
+	Line int
+	Err  error
+}
+
+// Error returns the problem with its line.
+func (a AuditError) Error() string {
+	return fmt.Sprintf("line %d: %s", a.Line, a.Err)
+}
+
+// Unwrap returns the problem, so it can be detected with errors.Is.
+func (a AuditError) Unwrap() error {
+	return a.Err
+}
+
+// AuditRecord stores who did what over which object. Each record contains the
+// hash of the previous one, so any modification or removal breaks the chain.
+type AuditRecord struct {
+	Sequence     uint64    `json:"seq"`
+	Time         time.Time `json:"time"`
+	Actor        string    `json:"actor"`
+	Action       string    `json:"action"`
+	Object       string    `json:"object"`
+	Fields       Fields    `json:"fields,omitempty"`
+	PreviousHash string    `json:"prev"`
+}
+
+// auditLine is the format of each line in the audit file. The record is kept
+// as raw JSON so the hash is always verified over the exact bytes written.
+type auditLine struct {
+	Record json.RawMessage `json:"record"`
+	Hash   string          `json:"hash"`
+}
+
+// AuditLogger writes append-only audit records with a hash chain. It is
+// separated from the operational logging, so audit records are never dropped
+// by levels, sampling or syslog failures. It is safe for concurrent use.
+type AuditLogger struct {
+	mutex    sync.Mutex
+	w        io.Writer
+	file     *os.File
+	sequence uint64
+	lastHash string
+}
+
+// NewAuditLogger returns an audit logger that starts a new hash chain in w.
+func NewAuditLogger(w io.Writer) *AuditLogger {
+	return &AuditLogger{w: w}
+}
+
+// OpenAudit opens or creates the audit file in append-only mode. An existing
+// file is verified before use, continuing its hash chain, and an error is
+// returned if it was tampered with.
+func OpenAudit(filename string) (*AuditLogger, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	sequence, lastHash, err := VerifyAudit(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &AuditLogger{
+		w:        file,
+		file:     file,
+		sequence: uint64(sequence),
+		lastHash: lastHash,
+	}, nil
+}
+
+// Record appends a new audit record informing that the actor executed the
+// action over the object (e.g. a domain name). Extra information can be stored
+// in fields. When the record is written but the file can't be synchronized the
+// error is returned, and the next records still continue the hash chain.
+func (a *AuditLogger) Record(actor, action, object string, fields Fields) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	record, err := json.Marshal(AuditRecord{
+		Sequence:     a.sequence + 1,
+		Time:         time.Now().UTC(),
+		Actor:        actor,
+		Action:       action,
+		Object:       object,
+		Fields:       fields,
+		PreviousHash: a.lastHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	hash := auditHash(record)
+	line, err := json.Marshal(auditLine{Record: record, Hash: hash})
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	// the record is already in the file, so the chain must continue from it
+	a.sequence++
+	a.lastHash = hash
+
+	if a.file != nil {
+		return a.file.Sync()
+	}
+	return nil
+}
+
+// Close closes the audit file when it was opened with OpenAudit.
+func (a *AuditLogger) Close() error {
+	if a.file == nil {
+		return nil
+	}
+	return a.file.Close()
+}
+
+// VerifyAudit reads all audit records checking the hash chain. It returns the
+// number of records and the hash of the last one, that should be stored
+// somewhere else to detect the removal of the latest records. Problems are
+// reported with AuditError.
+func VerifyAudit(r io.Reader) (records int, lastHash string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		var line auditLine
+		var record AuditRecord
+
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return records, lastHash, AuditError{Line: lineNumber, Err: ErrAuditMalformed}
+		}
+
+		if err := json.Unmarshal(line.Record, &record); err != nil {
+			return records, lastHash, AuditError{Line: lineNumber, Err: ErrAuditMalformed}
+		}
+
+		switch {
+		case auditHash(line.Record) != line.Hash:
+			return records, lastHash, AuditError{Line: lineNumber, Err: ErrAuditModified}
+		case record.Sequence != uint64(records)+1:
+			return records, lastHash, AuditError{Line: lineNumber, Err: ErrAuditGap}
+		case record.PreviousHash != lastHash:
+			return records, lastHash, AuditError{Line: lineNumber, Err: ErrAuditBrokenChain}
+		}
+
+		records++
+		lastHash = line.Hash
+	}
+
+	return records, lastHash, scanner.Err()
+}
+
+func auditHash(record []byte) string {
+	hash := sha256.Sum256(record)
+	return hex.EncodeToString(hash[:])
+}
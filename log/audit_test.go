@@ -0,0 +1,147 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyAudit(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf)
+
+	for _, domain := range []string{"example1.com.br", "example2.com.br", "example3.com.br"} {
+		if err := audit.Record("operator", "domain.update", domain, Fields{"id": 1 << 60}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+
+	scenarios := []struct {
+		description     string
+		content         string
+		expectedRecords int
+		expectedError   error
+	}{
+		{
+			description:     "it should verify a valid audit file",
+			content:         buf.String(),
+			expectedRecords: 3,
+		},
+		{
+			description:     "it should detect a modified record",
+			content:         lines[0] + strings.Replace(lines[1], "example2", "example9", 1) + lines[2],
+			expectedRecords: 1,
+			expectedError:   AuditError{Line: 2, Err: ErrAuditModified},
+		},
+		{
+			description:     "it should detect a removed record",
+			content:         lines[0] + lines[2],
+			expectedRecords: 1,
+			expectedError:   AuditError{Line: 2, Err: ErrAuditGap},
+		},
+		{
+			description:     "it should detect records in a different order",
+			content:         lines[1] + lines[0],
+			expectedRecords: 0,
+			expectedError:   AuditError{Line: 1, Err: ErrAuditGap},
+		},
+		{
+			description:     "it should detect a malformed record",
+			content:         lines[0] + "not a record\n",
+			expectedRecords: 1,
+			expectedError:   AuditError{Line: 2, Err: ErrAuditMalformed},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		records, _, err := VerifyAudit(strings.NewReader(scenario.content))
+
+		if err != scenario.expectedError {
+			t.Errorf("scenario %d, “%s”: mismatch errors. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedError, err)
+		}
+
+		if records != scenario.expectedRecords {
+			t.Errorf("scenario %d, “%s”: mismatch records. Expecting: %d; found %d",
+				i, scenario.description, scenario.expectedRecords, records)
+		}
+	}
+}
+
+func TestOpenAudit(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	for i := 0; i < 2; i++ {
+		audit, err := OpenAudit(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := audit.Record("operator", "domain.create", "example.com.br", nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := audit.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, lastHash, err := VerifyAudit(bytes.NewReader(content))
+	if err != nil || records != 2 || lastHash == "" {
+		t.Fatalf("unexpected verification result: %d records, hash “%s”, error “%v”", records, lastHash, err)
+	}
+
+	tampered := bytes.Replace(content, []byte("domain.create"), []byte("domain.delete"), 1)
+	if err := os.WriteFile(filename, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedError := AuditError{Line: 1, Err: ErrAuditModified}
+	if _, err := OpenAudit(filename); err != expectedError {
+		t.Errorf("mismatch errors. Expecting: “%v”; found “%v”", expectedError, err)
+	}
+}
+
+func TestAuditLogger_Record_syncFailure(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// pipes can't be synchronized, but the records are written
+	audit := &AuditLogger{w: w, file: w}
+	for _, domain := range []string{"example1.com.br", "example2.com.br"} {
+		if err := audit.Record("operator", "domain.update", domain, nil); err == nil {
+			t.Error("synchronization error not reported")
+		}
+	}
+	w.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if records, _, err := VerifyAudit(bytes.NewReader(content)); err != nil || records != 2 {
+		t.Errorf("unexpected verification result: %d records, error “%v”", records, err)
+	}
+}
+
+func TestAuditError_Unwrap(t *testing.T) {
+	_, _, err := VerifyAudit(strings.NewReader("not a record\n"))
+	if !errors.Is(err, ErrAuditMalformed) {
+		t.Errorf("mismatch errors. Expecting: “%v”; found “%v”", ErrAuditMalformed, err)
+	}
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+func BenchmarkLogger_disabled(b *testing.B) {
+	core := NewCore()
+	core.SetLocalLogger(log.New(io.Discard, "", 0))
+	core.SetLevel(LevelInfo)
+	l := core.NewLogger("test")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Debugf("this is the message %s", "test")
+	}
+}
+
+func BenchmarkLogger_Info(b *testing.B) {
+	core := NewCore()
+	core.SetLocalLogger(log.New(io.Discard, "", 0))
+	core.SetLocalEncoder(TextEncoder{})
+	l := core.NewLogger("test")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Info("this is a message")
+	}
+}
+
+func BenchmarkInfo(b *testing.B) {
+	originalLocalLogger := LocalLogger
+	originalSyslog := std.syslog
+	defer func() {
+		LocalLogger = originalLocalLogger
+		std.syslog = originalSyslog
+	}()
+
+	LocalLogger = log.New(io.Discard, "", 0)
+	std.syslog = nil
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Info("this is a message")
+	}
+}
+
+func BenchmarkDebug_disabled(b *testing.B) {
+	originalLevel := std.Level()
+	defer std.SetLevel(originalLevel)
+	std.SetLevel(LevelInfo)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Debug("this is a message")
+	}
+}
+
+func TestLogger_allocations(t *testing.T) {
+	core := NewCore()
+	core.SetLocalLogger(log.New(io.Discard, "", 0))
+	core.SetLocalEncoder(TextEncoder{})
+	core.SetLevel(LevelInfo)
+	l := core.NewLogger("test")
+
+	if allocs := testing.AllocsPerRun(100, func() { l.Debug("this is a message") }); allocs > 0 {
+		t.Errorf("disabled message allocating memory: %.0f allocations", allocs)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { l.Info("this is a message") }); allocs > 1 {
+		t.Errorf("simple message allocating too much memory: %.0f allocations", allocs)
+	}
+}
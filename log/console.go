@@ -0,0 +1,113 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// consoleLocationWidth is the minimum width of the location column, so the
+// messages are aligned in the console.
+const consoleLocationWidth = 32
+
+// ANSI escape codes used to color the console output.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+	colorGray   = "\x1b[90m"
+	colorBold   = "\x1b[1m"
+)
+
+// levelColors defines the color of each log level in the console.
+var levelColors = map[Level]string{
+	LevelEmergency: colorBold + colorRed,
+	LevelAlert:     colorBold + colorRed,
+	LevelCritical:  colorBold + colorRed,
+	LevelError:     colorRed,
+	LevelWarning:   colorYellow,
+	LevelNotice:    colorCyan,
+	LevelInfo:      colorGreen,
+	LevelDebug:     colorBlue,
+}
+
+// ConsoleEncoder encodes the log entries in a human-oriented format, useful
+// when running services locally:
+//
+//    WARNING gostk/log/file.go:42             [identifier] message key=value
+//
+// The level is colored when Colors is enabled.
+type ConsoleEncoder struct {
+	// Colors enables the ANSI colors in the output.
+	Colors bool
+}
+
+// NewConsoleEncoder returns a ConsoleEncoder that will write its output to w.
+// Colors are enabled only when w is a terminal and the NO_COLOR environment
+// variable isn't defined.
+func NewConsoleEncoder(w io.Writer) ConsoleEncoder {
+	return ConsoleEncoder{
+		Colors: isTerminal(w) && os.Getenv("NO_COLOR") == "",
+	}
+}
+
+// Encode writes the console representation of the entry in the buffer.
+func (c ConsoleEncoder) Encode(buf *bytes.Buffer, e Entry) {
+	color := levelColors[e.Level]
+
+	c.colorize(buf, color, fmt.Sprintf("%-7s", strings.ToUpper(e.Level.String())))
+	buf.WriteByte(' ')
+
+	var location string
+	if e.File != "" {
+		location = e.File + ":" + strconv.Itoa(e.Line)
+	}
+	c.colorize(buf, colorGray, fmt.Sprintf("%-*s", consoleLocationWidth, location))
+	buf.WriteByte(' ')
+
+	if e.Identifier != "" {
+		buf.WriteByte('[')
+		buf.WriteString(e.Identifier)
+		buf.WriteString("] ")
+	}
+
+	buf.WriteString(e.Message)
+
+	for _, key := range e.Fields.keys() {
+		buf.WriteByte(' ')
+		c.colorize(buf, color, key)
+		fmt.Fprintf(buf, "=%v", e.Fields[key])
+	}
+}
+
+func (c ConsoleEncoder) colorize(buf *bytes.Buffer, color, text string) {
+	if !c.Colors || color == "" {
+		buf.WriteString(text)
+		return
+	}
+
+	buf.WriteString(color)
+	buf.WriteString(text)
+	buf.WriteString(colorReset)
+}
+
+// isTerminal checks if the writer is a character device, as the terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestConsoleEncoder_Encode(t *testing.T) {
+	scenarios := []struct {
+		description string
+		encoder     ConsoleEncoder
+		entry       Entry
+		expected    string
+	}{
+		{
+			description: "it should encode an entry without colors",
+			entry: Entry{
+				Level:      LevelWarning,
+				Identifier: "test",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "this is a message",
+				Fields:     Fields{"zone": "br"},
+			},
+			expected: "WARNING gostk/log/file.go:42             [test] this is a message zone=br",
+		},
+		{
+			description: "it should align entries without location or identifier",
+			entry: Entry{
+				Level:   LevelInfo,
+				Message: "this is a message",
+			},
+			expected: "INFO                                     this is a message",
+		},
+		{
+			description: "it should color the level and the fields",
+			encoder:     ConsoleEncoder{Colors: true},
+			entry: Entry{
+				Level:   LevelError,
+				File:    "gostk/log/file.go",
+				Line:    42,
+				Message: "this is a message",
+				Fields:  Fields{"zone": "br"},
+			},
+			expected: "\x1b[31mERR    \x1b[0m \x1b[90mgostk/log/file.go:42            \x1b[0m this is a message \x1b[31mzone\x1b[0m=br",
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var buf bytes.Buffer
+		scenario.encoder.Encode(&buf, scenario.entry)
+
+		if result := buf.String(); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%q”; found “%q”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestNewConsoleEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	if encoder := NewConsoleEncoder(&buf); encoder.Colors {
+		t.Error("colors should be disabled when the output isn't a terminal")
+	}
+
+	f, err := os.CreateTemp("", "gostk-log-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if encoder := NewConsoleEncoder(f); encoder.Colors {
+		t.Error("colors should be disabled when the output is a regular file")
+	}
+}
@@ -0,0 +1,553 @@
+package log
+
+import (
+	"context"
+	"log"
+	"log/syslog"
+	"os"
+	"sync"
+	"time"
+)
+
+// namedSink stores a sink with its registration name.
+type namedSink struct {
+	name string
+	sink Sink
+}
+
+// namedHook stores a hook with its registration name.
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Core is an independent logging destination, with its own syslog connection,
+// local logger, encoders, size limits, level, hooks and sinks. Loggers are derived
+// from a Core with NewLogger, so different components of the same process can
+// log to different places. The package functions use a default Core (see
+// Default). It is safe for concurrent use.
+type Core struct {
+	mutex sync.RWMutex
+
+	syslog        syslogWriter
+	syslogNetwork string
+	syslogHeader  int
+	syslogEncoder Encoder
+	syslogLimit   SizeLimit
+
+	// local is nil only in the default Core, that uses the package variable
+	// LocalLogger for compatibility.
+	local        *log.Logger
+	localEncoder Encoder
+	localLimit   SizeLimit
+
+	// localEncoderSet indicates that the local encoder was chosen with
+	// SetLocalEncoder, so it isn't replaced when the local logger changes.
+	// Otherwise the encoder is chosen for the writer of localEncoderFor, and
+	// detected again when a different local logger is used (e.g. when the
+	// package variable LocalLogger is replaced).
+	localEncoderSet bool
+	localEncoderFor *log.Logger
+
+	level   Level
+	sampler Sampler
+
+	// hooks and sinks are never modified, only replaced, so they can be
+	// iterated without holding the lock.
+	hooks []namedHook
+	sinks []namedSink
+
+	counters counters
+}
+
+// NewCore returns a Core without syslog connection, logging all levels to the
+// standard error. The local format can be changed with the environment
+// variable GOSTK_LOG_FORMAT (see EncoderFromEnv).
+func NewCore() *Core {
+	c := newCore()
+	c.local = log.New(os.Stderr, "", log.LstdFlags)
+	c.localEncoderFor = c.local
+	return c
+}
+
+func newCore() *Core {
+	return &Core{
+		syslogEncoder: TextEncoder{},
+		localEncoder:  EncoderFromEnv(os.Stderr),
+		level:         LevelDebug,
+	}
+}
+
+// std is the default Core used by the package functions.
+var std = newCore()
+
+// Default returns the Core used by the package functions, that writes to the
+// LocalLogger when there's no syslog connection.
+func Default() *Core {
+	return std
+}
+
+// Dial establishes a connection to a log daemon by connecting to address raddr
+// on the specified network. Check the package function Dial for more details.
+func (c *Core) Dial(network, raddr, tag string, timeout time.Duration) error {
+	// The channels has size of 1 (buffered) to avoid keeping an unnecessary goroutine blocked in
+	// memory. For example: a goroutine is spawn, and it returns via channel a new transaction or
+	// an error. After spawning a goroutine the program blocks in the select statement waiting
+	// until the first channel message. In case of a timeout message, the spawned goroutine will
+	// put a message in one of this two channels (ch and chErr) and simply returns (die), the
+	// program don't care about the messages, because it has already timed out. If the channels
+	// were not buffered the goroutine would be blocked trying to put a message into the channel
+	// until the program dies.
+	ch := make(chan *syslog.Writer, 1)
+	chErr := make(chan error, 1)
+
+	go func() {
+		w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+		if err != nil {
+			chErr <- err
+			return
+		}
+
+		ch <- w
+	}()
+
+	select {
+	case w := <-ch:
+		c.mutex.Lock()
+		previous := c.syslog
+		c.syslog = w
+		c.syslogNetwork = network
+		c.syslogHeader = syslogHeaderSize(network, tag)
+		c.mutex.Unlock()
+
+		if previous != nil {
+			// the previous connection isn't used anymore, and closing it
+			// can't fail the new one
+			previous.Close()
+		}
+
+		c.counters.countDial()
+		return nil
+	case err := <-chErr:
+		return err
+	case <-time.After(timeout):
+		return ErrDialTimeout
+	}
+}
+
+// Close closes the connection to the syslog daemon.
+func (c *Core) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.syslog == nil {
+		return nil
+	}
+
+	err := c.syslog.Close()
+	if err == nil {
+		c.syslog = nil
+	}
+	return err
+}
+
+// SetLevel defines the lowest priority logged. For example, with LevelInfo the
+// debug messages are ignored.
+func (c *Core) SetLevel(level Level) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.level = level
+}
+
+// Level returns the lowest priority logged.
+func (c *Core) Level() Level {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.level
+}
+
+// SetSampler defines the sampling strategy for the low priority entries. A nil
+// sampler logs all entries.
+func (c *Core) SetSampler(s Sampler) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sampler = s
+}
+
+// SetSyslogEncoder defines the format of the messages sent to the syslog
+// server. The syslog server already stores the time and the level of each
+// message, so by default only the identifier, location and message are sent
+// (TextEncoder).
+func (c *Core) SetSyslogEncoder(enc Encoder) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.syslogEncoder = enc
+}
+
+// SetSyslogLimit defines the maximum size of the messages sent to the syslog
+// server, including the syslog header. By default it uses the size of the
+// network informed in Dial (see MaxMessageSizes).
+func (c *Core) SetSyslogLimit(limit SizeLimit) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.syslogLimit = limit
+}
+
+// SetLocalLogger defines the fallback log used when the syslog server isn't
+// available. Unless an encoder was defined with SetLocalEncoder, the local
+// format is chosen again for the new writer (see EncoderFromEnv), so colors
+// are only written to terminals.
+func (c *Core) SetLocalLogger(l *log.Logger) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.local = l
+
+	if !c.localEncoderSet {
+		c.localEncoder = EncoderFromEnv(l.Writer())
+		c.localEncoderFor = l
+	}
+}
+
+// SetLocalEncoder defines the format of the messages written in the local
+// logger.
+func (c *Core) SetLocalEncoder(enc Encoder) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.localEncoder = enc
+	c.localEncoderSet = true
+}
+
+// SetLocalLimit defines the maximum size of the messages written in the local
+// logger. By default there's no limit.
+func (c *Core) SetLocalLimit(limit SizeLimit) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.localLimit = limit
+}
+
+// AddHook registers a hook that processes the entries before they are written.
+// The name replaces any hook previously registered with the same name, keeping
+// its position.
+func (c *Core) AddHook(name string, h Hook) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hooks := make([]namedHook, 0, len(c.hooks)+1)
+	replaced := false
+	for _, item := range c.hooks {
+		if item.name == name {
+			item.hook = h
+			replaced = true
+		}
+		hooks = append(hooks, item)
+	}
+
+	if !replaced {
+		hooks = append(hooks, namedHook{name: name, hook: h})
+	}
+
+	c.hooks = hooks
+}
+
+// RemoveHook unregisters the hook identified by name.
+func (c *Core) RemoveHook(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hooks := make([]namedHook, 0, len(c.hooks))
+	for _, item := range c.hooks {
+		if item.name != name {
+			hooks = append(hooks, item)
+		}
+	}
+
+	c.hooks = hooks
+}
+
+// AddSink registers an extra destination for the log entries. The name
+// identifies the sink in the metrics and replaces any sink previously
+// registered with the same name.
+func (c *Core) AddSink(name string, s Sink) {
+	if binder, ok := s.(coreBinder); ok {
+		binder.bindCore(c)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sinks := make([]namedSink, 0, len(c.sinks)+1)
+	replaced := false
+	for _, item := range c.sinks {
+		if item.name == name {
+			item.sink = s
+			replaced = true
+		}
+		sinks = append(sinks, item)
+	}
+
+	if !replaced {
+		sinks = append(sinks, namedSink{name: name, sink: s})
+	}
+
+	c.sinks = sinks
+}
+
+// RemoveSink unregisters the destination identified by name, returning it so
+// the caller can close it. If there's no sink with the name nil is returned.
+func (c *Core) RemoveSink(name string) Sink {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var removed Sink
+	sinks := make([]namedSink, 0, len(c.sinks))
+	for _, item := range c.sinks {
+		if item.name == name {
+			removed = item.sink
+			continue
+		}
+		sinks = append(sinks, item)
+	}
+
+	c.sinks = sinks
+	return removed
+}
+
+// Flush sends the buffered entries of all registered sinks, returning the
+// first error found.
+func (c *Core) Flush() error {
+	c.mutex.RLock()
+	sinks := c.sinks
+	c.mutex.RUnlock()
+
+	var err error
+	for _, item := range sinks {
+		if f, ok := item.sink.(Flusher); ok {
+			if flushErr := f.Flush(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+		}
+	}
+	return err
+}
+
+// shutdownGrace is the extra time given to the sinks when the shutdown
+// deadline is exceeded, so the buffered entries are not silently lost.
+const shutdownGrace = time.Second
+
+// Shutdown flushes and closes all registered sinks and the connection with the
+// syslog server, waiting until the context is done. The sinks are unregistered,
+// so the entries logged afterwards go only to the local logger. Even when the
+// deadline is exceeded the sinks are closed, waiting a short grace period. It
+// returns the first error found or the context error when the deadline is
+// exceeded.
+func (c *Core) Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	go func() {
+		c.mutex.Lock()
+		sinks := c.sinks
+		c.sinks = nil
+		c.mutex.Unlock()
+
+		var err error
+		for _, item := range sinks {
+			if closeErr := item.sink.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+
+		if closeErr := c.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+
+	case <-ctx.Done():
+	}
+
+	grace := time.NewTimer(shutdownGrace)
+	defer grace.Stop()
+
+	select {
+	case <-done:
+	case <-grace.C:
+	}
+
+	return ctx.Err()
+}
+
+// NewLogger returns a Logger that writes to this Core, tagging an identifier
+// to every message logged.
+func (c *Core) NewLogger(id string) Logger {
+	return &logger{
+		core:       c,
+		identifier: id,
+		caller:     callerSkip,
+	}
+}
+
+// enabled checks if the entries of the level should be logged.
+func (c *Core) enabled(level Level) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return level <= c.level
+}
+
+// sampled checks if the sampler keeps the entries of the level and identifier.
+func (c *Core) sampled(level Level, identifier string) bool {
+	c.mutex.RLock()
+	sampler := c.sampler
+	c.mutex.RUnlock()
+
+	if sampler != nil && !sampler.Sample(level, identifier) {
+		c.counters.countSampled()
+		return false
+	}
+	return true
+}
+
+// syslogFunc returns the syslog writer function for the given level, or nil if
+// there's no connection with the syslog server.
+func syslogFunc(w syslogWriter, level Level) logFunc {
+	if w == nil {
+		return nil
+	}
+
+	switch level {
+	case LevelEmergency:
+		return w.Emerg
+	case LevelAlert:
+		return w.Alert
+	case LevelCritical:
+		return w.Crit
+	case LevelWarning:
+		return w.Warning
+	case LevelNotice:
+		return w.Notice
+	case LevelInfo:
+		return w.Info
+	case LevelDebug:
+		return w.Debug
+	}
+
+	return w.Err
+}
+
+// write sends the entry to the syslog server, falling back to the local logger
+// when there's no connection or the syslog write fails. The entry is also sent
+// to all registered sinks. The hooks are called before, and can drop it.
+func (c *Core) write(e Entry) {
+	c.mutex.RLock()
+	w, network, header, enc, limit := c.syslog, c.syslogNetwork, c.syslogHeader, c.syslogEncoder, c.syslogLimit
+	hooks, sinks := c.hooks, c.sinks
+	c.mutex.RUnlock()
+
+	if len(hooks) > 0 {
+		var keep bool
+		if e, keep = fireHooks(hooks, e); !keep {
+			return
+		}
+	}
+
+	c.counters.countLevel(e.Level)
+
+	defer c.writeSinks(sinks, e)
+
+	f := syslogFunc(w, e.Level)
+	if f == nil {
+		c.writeLocal(e)
+		return
+	}
+
+	err := limit.write(enc, e, MaxMessageSizes[network], header, func(msg []byte) error {
+		err := f(string(msg))
+		c.counters.countSink(sinkSyslog, err)
+		return err
+	})
+
+	if err != nil {
+		c.localLogger().Println("Error writing to syslog. Details:", err)
+		c.writeLocal(e)
+	}
+}
+
+func (c *Core) writeLocal(e Entry) {
+	local := c.localLogger()
+
+	c.mutex.RLock()
+	enc, limit := c.localEncoder, c.localLimit
+	detect := !c.localEncoderSet && c.localEncoderFor != local
+	c.mutex.RUnlock()
+
+	if detect {
+		enc = c.detectLocalEncoder(local)
+	}
+
+	limit.write(enc, e, 0, 0, func(msg []byte) error {
+		err := local.Output(2, string(msg))
+		c.counters.countSink(sinkLocal, err)
+
+		if err != nil {
+			c.counters.countDropped()
+		}
+		// keep writing the other parts, as there's no other fallback
+		return nil
+	})
+}
+
+// detectLocalEncoder chooses the local encoder for the writer of the local
+// logger, as it may not be a terminal anymore.
+func (c *Core) detectLocalEncoder(local *log.Logger) Encoder {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.localEncoderSet && c.localEncoderFor != local {
+		c.localEncoder = EncoderFromEnv(local.Writer())
+		c.localEncoderFor = local
+	}
+	return c.localEncoder
+}
+
+// fireHooks calls the hooks in order, returning the modified entry and false
+// when it was dropped. It receives a copy of the entry so only the entries
+// processed by hooks escape to the heap.
+func fireHooks(hooks []namedHook, e Entry) (Entry, bool) {
+	for _, item := range hooks {
+		if !item.hook.Fire(&e) {
+			return e, false
+		}
+	}
+	return e, true
+}
+
+// writeSinks sends the entry to all registered sinks.
+func (c *Core) writeSinks(sinks []namedSink, e Entry) {
+	for _, item := range sinks {
+		err := item.sink.Write(e)
+		c.counters.countSink(item.name, err)
+
+		if err != nil {
+			c.counters.countDropped()
+			c.localLogger().Printf("Error writing to sink %s. Details: %s", item.name, err)
+		}
+	}
+}
+
+// localLogger returns the fallback logger of the Core.
+func (c *Core) localLogger() *log.Logger {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.local == nil {
+		return LocalLogger
+	}
+	return c.local
+}
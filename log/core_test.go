@@ -0,0 +1,222 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCore_NewLogger(t *testing.T) {
+	var firstBuffer, secondBuffer bytes.Buffer
+
+	first := NewCore()
+	first.SetLocalLogger(log.New(&firstBuffer, "", 0))
+	first.SetLocalEncoder(TextEncoder{})
+
+	second := NewCore()
+	second.SetLocalLogger(log.New(&secondBuffer, "", 0))
+	second.SetLocalEncoder(TextEncoder{})
+
+	var syslogMessages []string
+	second.syslog = mockSyslogWriter{
+		mockNotice: func(msg string) error {
+			syslogMessages = append(syslogMessages, msg)
+			return nil
+		},
+	}
+
+	first.NewLogger("first").Notice("message 1")
+	second.NewLogger("second").Notice("message 2")
+
+	if result := strings.TrimSpace(firstBuffer.String()); !strings.HasSuffix(result, "message 1") {
+		t.Errorf("mismatch first core output: “%s”", result)
+	}
+
+	if secondBuffer.Len() > 0 {
+		t.Errorf("unexpected second core local output: “%s”", secondBuffer.String())
+	}
+
+	if len(syslogMessages) != 1 || !strings.HasSuffix(syslogMessages[0], "message 2") {
+		t.Errorf("mismatch second core syslog output: “%v”", syslogMessages)
+	}
+}
+
+func TestCore_Dial(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func(l net.Listener) {
+		if _, err := l.Accept(); err != nil {
+			return
+		}
+	}(l)
+
+	previousClosed := false
+
+	core := NewCore()
+	core.syslog = mockSyslogWriter{
+		mockClose: func() error {
+			previousClosed = true
+			return nil
+		},
+	}
+
+	if err := core.Dial("tcp", l.Addr().String(), "test", time.Second); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer core.Close()
+
+	if !previousClosed {
+		t.Error("previous syslog connection not closed")
+	}
+
+	if dials := core.Metrics().Dials; dials != 1 {
+		t.Errorf("mismatch dials. Expecting: 1; found %d", dials)
+	}
+}
+
+func TestCore_SetLocalLogger(t *testing.T) {
+	original := os.Getenv("GOSTK_LOG_FORMAT")
+	defer os.Setenv("GOSTK_LOG_FORMAT", original)
+	os.Setenv("GOSTK_LOG_FORMAT", "console")
+
+	var buf bytes.Buffer
+
+	core := NewCore()
+	core.SetLocalLogger(log.New(&buf, "", 0))
+
+	if expected := (ConsoleEncoder{}); core.localEncoder != expected {
+		t.Errorf("mismatch encoders. Expecting: “%#v”; found “%#v”", expected, core.localEncoder)
+	}
+
+	core.SetLocalEncoder(LogfmtEncoder{})
+	core.SetLocalLogger(log.New(&buf, "", 0))
+
+	if expected := (LogfmtEncoder{}); core.localEncoder != expected {
+		t.Errorf("mismatch encoders. Expecting: “%#v”; found “%#v”", expected, core.localEncoder)
+	}
+}
+
+func TestCore_SetLevel(t *testing.T) {
+	scenarios := []struct {
+		description string
+		level       Level
+		log         func(l Logger)
+		expected    string
+	}{
+		{
+			description: "it should log all levels by default",
+			level:       LevelDebug,
+			log: func(l Logger) {
+				l.Debug("debug message")
+			},
+			expected: "debug message",
+		},
+		{
+			description: "it should ignore lower priority messages",
+			level:       LevelInfo,
+			log: func(l Logger) {
+				l.Debugf("debug %s", "message")
+			},
+		},
+		{
+			description: "it should ignore lower priority errors",
+			level:       LevelError,
+			log: func(l Logger) {
+				l.Error(levelError{msg: "warning message", level: LevelWarning})
+			},
+		},
+		{
+			description: "it should log higher priority messages",
+			level:       LevelError,
+			log: func(l Logger) {
+				l.Error(fmt.Errorf("error message"))
+			},
+			expected: "[test] error message",
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var localBuffer bytes.Buffer
+		core := NewCore()
+		core.SetLocalLogger(log.New(&localBuffer, "", 0))
+		core.SetLocalEncoder(TextEncoder{})
+		core.SetLevel(scenario.level)
+
+		if level := core.Level(); level != scenario.level {
+			t.Errorf("scenario %d, “%s”: mismatch level. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.level, level)
+		}
+
+		scenario.log(core.NewLogger("test"))
+
+		result := strings.TrimSpace(localBuffer.String())
+		if (scenario.expected == "" && result != "") || !strings.HasSuffix(result, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestCore_parallel(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("core%d", i)
+
+		t.Run(id, func(t *testing.T) {
+			t.Parallel()
+
+			var localBuffer bytes.Buffer
+			core := NewCore()
+			core.SetLocalLogger(log.New(&localBuffer, "", 0))
+			core.SetLocalEncoder(TextEncoder{})
+
+			l := core.NewLogger(id)
+			for j := 0; j < 100; j++ {
+				l.Infof("message %d", j)
+			}
+
+			if lines := strings.Count(localBuffer.String(), "["+id+"]"); lines != 100 {
+				t.Errorf("mismatch number of messages. Expecting: 100; found %d", lines)
+			}
+
+			if m := core.Metrics(); m.Levels["info"] != 100 {
+				t.Errorf("mismatch metrics. Expecting: 100; found %d", m.Levels["info"])
+			}
+		})
+	}
+}
+
+func TestCore_LocalLogger_replaced(t *testing.T) {
+	original := os.Getenv("GOSTK_LOG_FORMAT")
+	defer os.Setenv("GOSTK_LOG_FORMAT", original)
+	os.Setenv("GOSTK_LOG_FORMAT", "console")
+
+	originalLocalLogger := LocalLogger
+	defer func() {
+		LocalLogger = originalLocalLogger
+	}()
+
+	var buf bytes.Buffer
+
+	core := newCore()
+	core.localEncoder = ConsoleEncoder{Colors: true}
+	LocalLogger = log.New(&buf, "", 0)
+
+	core.NewLogger("test").Error(fmt.Errorf("error detected"))
+
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("colors written to a writer that isn't a terminal: “%q”", buf.String())
+	}
+
+	if expected := (ConsoleEncoder{}); core.localEncoder != expected {
+		t.Errorf("mismatch encoders. Expecting: “%#v”; found “%#v”", expected, core.localEncoder)
+	}
+}
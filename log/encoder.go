@@ -0,0 +1,244 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/registrobr/gostk/path"
+	"github.com/registrobr/gostk/runtime"
+)
+
+// Fields stores extra information attached to a log entry, where each key
+// identifies the value.
+type Fields map[string]interface{}
+
+// keys returns the fields keys in alphabetical order, so the encoded output is
+// always the same.
+func (f Fields) keys() []string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// merge returns a new map with the fields of both maps, where the other fields
+// replace the ones with the same key.
+func (f Fields) merge(other Fields) Fields {
+	merged := make(Fields, len(f)+len(other))
+	for key, value := range f {
+		merged[key] = value
+	}
+	for key, value := range other {
+		merged[key] = value
+	}
+	return merged
+}
+
+// Entry stores all the information of a single log message.
+type Entry struct {
+	Time       time.Time
+	Level      Level
+	Identifier string
+	File       string
+	Line       int
+	Message    string
+	Fields     Fields
+
+	// Err is the original error when the entry was logged with Logger.Error.
+	// Structured encoders use it to add the error details (see ErrorDetails).
+	Err error
+}
+
+// locator is implemented by errors that store where they were created, like
+// the ones from the gostk errors package.
+type locator interface {
+	Location() (file string, line int)
+}
+
+// framer is implemented by errors that captured the call stack.
+type framer interface {
+	Frames() []runtime.Frame
+}
+
+// ErrorDetails stores the structured information of an error.
+type ErrorDetails struct {
+	// Type is the Go type of the error.
+	Type string `json:"type"`
+
+	// Chain contains the types of the wrapped errors, from the outermost to
+	// the innermost one, when there's more than one.
+	Chain []string `json:"chain,omitempty"`
+
+	// File and Line are the location of the first error in the chain that
+	// knows where it was created.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// Level is the syslog name of the error level.
+	Level string `json:"level"`
+
+	// Stack is the call stack of the innermost error in the chain that
+	// captured it, with only the relevant directories of the files.
+	Stack []runtime.Frame `json:"stack,omitempty"`
+}
+
+// NewErrorDetails walks the chain of wrapped errors retrieving its structured
+// information. It returns nil when there's no error.
+func NewErrorDetails(err error) *ErrorDetails {
+	if err == nil {
+		return nil
+	}
+
+	details := &ErrorDetails{
+		Type:  fmt.Sprintf("%T", err),
+		Level: errorLevel(err).String(),
+	}
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		details.Chain = append(details.Chain, fmt.Sprintf("%T", current))
+
+		if l, ok := current.(locator); ok && details.File == "" {
+			details.File, details.Line = l.Location()
+		}
+
+		if f, ok := current.(framer); ok {
+			if frames := f.Frames(); len(frames) > 0 {
+				details.Stack = make([]runtime.Frame, len(frames))
+				for i, frame := range frames {
+					frame.File = path.RelevantPath(frame.File, pathDeep)
+					details.Stack[i] = frame
+				}
+			}
+		}
+	}
+
+	if len(details.Chain) == 1 {
+		details.Chain = nil
+	}
+
+	return details
+}
+
+// MarshalJSON returns the JSON representation of the entry, using the syslog
+// name of the level. The original error is represented by its details.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Time       time.Time     `json:"time"`
+		Level      string        `json:"level"`
+		Identifier string        `json:"id,omitempty"`
+		File       string        `json:"file,omitempty"`
+		Line       int           `json:"line,omitempty"`
+		Message    string        `json:"message"`
+		Fields     Fields        `json:"fields,omitempty"`
+		Error      *ErrorDetails `json:"error,omitempty"`
+	}{
+		Time:       e.Time,
+		Level:      e.Level.String(),
+		Identifier: e.Identifier,
+		File:       e.File,
+		Line:       e.Line,
+		Message:    e.Message,
+		Fields:     e.Fields,
+		Error:      NewErrorDetails(e.Err),
+	})
+}
+
+// Encoder converts a log entry into its text representation.
+type Encoder interface {
+	Encode(buf *bytes.Buffer, e Entry)
+}
+
+// buffers reuses the encoding buffers, reducing the allocations when logging.
+var buffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// encode is a shortcut to retrieve the text representation of an entry.
+func encode(enc Encoder, e Entry) string {
+	buf := buffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	enc.Encode(buf, e)
+	msg := buf.String()
+	buffers.Put(buf)
+	return msg
+}
+
+// encodedLen returns the size of the text representation of an entry.
+func encodedLen(enc Encoder, e Entry) int {
+	buf := buffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	enc.Encode(buf, e)
+	n := buf.Len()
+	buffers.Put(buf)
+	return n
+}
+
+// EncoderFromEnv returns the encoder defined in the environment variable
+// GOSTK_LOG_FORMAT, where the output will be written to w. The supported values
+// are "text" (default), "console" and "logfmt".
+func EncoderFromEnv(w io.Writer) Encoder {
+	switch os.Getenv("GOSTK_LOG_FORMAT") {
+	case "console":
+		return NewConsoleEncoder(w)
+	case "logfmt":
+		return LogfmtEncoder{}
+	}
+
+	return TextEncoder{}
+}
+
+// TextEncoder encodes the log entries in the default gostk format:
+//
+//    [identifier] file:line: message key=value
+//
+// The location is omitted when it is unknown, as in entries created from
+// errors. When the error has the call stack (see errors.WithStack) its
+// locations are written at the end, from the innermost call:
+//
+//    [identifier] file:line: message key=value error.stack=file:line>file:line
+type TextEncoder struct{}
+
+// Encode writes the text representation of the entry in the buffer.
+func (TextEncoder) Encode(buf *bytes.Buffer, e Entry) {
+	buf.WriteByte('[')
+	buf.WriteString(e.Identifier)
+	buf.WriteString("] ")
+
+	if e.File != "" {
+		var line [20]byte
+		buf.WriteString(e.File)
+		buf.WriteByte(':')
+		buf.Write(strconv.AppendInt(line[:0], int64(e.Line), 10))
+		buf.WriteString(": ")
+	}
+
+	buf.WriteString(e.Message)
+
+	for _, key := range e.Fields.keys() {
+		fmt.Fprintf(buf, " %s=%v", key, e.Fields[key])
+	}
+
+	if details := NewErrorDetails(e.Err); details != nil && len(details.Stack) > 0 {
+		buf.WriteString(" error.stack=")
+		for i, frame := range details.Stack {
+			if i > 0 {
+				buf.WriteByte('>')
+			}
+			buf.WriteString(frame.File)
+			buf.WriteByte(':')
+			buf.WriteString(strconv.Itoa(frame.Line))
+		}
+	}
+}
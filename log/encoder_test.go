@@ -0,0 +1,226 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/registrobr/gostk/runtime"
+)
+
+func TestTextEncoder_Encode(t *testing.T) {
+	scenarios := []struct {
+		description string
+		entry       Entry
+		expected    string
+	}{
+		{
+			description: "it should encode an entry correctly",
+			entry: Entry{
+				Time:       time.Now(),
+				Level:      LevelInfo,
+				Identifier: "test",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "this is a message",
+			},
+			expected: "[test] gostk/log/file.go:42: this is a message",
+		},
+		{
+			description: "it should omit an unknown location",
+			entry: Entry{
+				Level:   LevelError,
+				Message: "this is an error",
+			},
+			expected: "[] this is an error",
+		},
+		{
+			description: "it should encode the fields in alphabetical order",
+			entry: Entry{
+				Level:      LevelInfo,
+				Identifier: "test",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "this is a message",
+				Fields:     Fields{"zone": "br", "domain": "example.com.br"},
+			},
+			expected: "[test] gostk/log/file.go:42: this is a message domain=example.com.br zone=br",
+		},
+		{
+			description: "it should encode the error stack",
+			entry: Entry{
+				Level:   LevelCritical,
+				Message: "failure detected",
+				Err: locatedError{
+					err:   fmt.Errorf("failure detected"),
+					level: LevelCritical,
+					frames: []runtime.Frame{
+						{Function: "main.handle", File: "/home/user/go/src/project/handler/handler.go", Line: 42},
+						{Function: "main.main", File: "/home/user/go/src/project/main.go", Line: 10},
+					},
+				},
+			},
+			expected: "[] failure detected error.stack=project/handler/handler.go:42>src/project/main.go:10",
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var buf bytes.Buffer
+		TextEncoder{}.Encode(&buf, scenario.entry)
+
+		if result := buf.String(); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestEncoderFromEnv(t *testing.T) {
+	scenarios := []struct {
+		description string
+		format      string
+		expected    Encoder
+	}{
+		{
+			description: "it should use the text encoder by default",
+			expected:    TextEncoder{},
+		},
+		{
+			description: "it should use the console encoder",
+			format:      "console",
+			expected:    ConsoleEncoder{},
+		},
+		{
+			description: "it should use the logfmt encoder",
+			format:      "logfmt",
+			expected:    LogfmtEncoder{},
+		},
+		{
+			description: "it should use the text encoder for unknown formats",
+			format:      "unknown",
+			expected:    TextEncoder{},
+		},
+	}
+
+	original := os.Getenv("GOSTK_LOG_FORMAT")
+	defer os.Setenv("GOSTK_LOG_FORMAT", original)
+
+	for i, scenario := range scenarios {
+		os.Setenv("GOSTK_LOG_FORMAT", scenario.format)
+
+		var buf bytes.Buffer
+		if encoder := EncoderFromEnv(&buf); !reflect.DeepEqual(encoder, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%#v”; found “%#v”",
+				i, scenario.description, scenario.expected, encoder)
+		}
+	}
+}
+
+func TestLogger_WithFields(t *testing.T) {
+	var localBuffer bytes.Buffer
+	core := NewCore()
+	core.SetLocalLogger(log.New(&localBuffer, "", 0))
+	core.SetLocalEncoder(TextEncoder{})
+
+	parent := core.NewLogger("test").(FieldLogger).WithFields(Fields{"domain": "example.com.br"})
+	child := parent.WithFields(Fields{"zone": "br"})
+
+	parent.Error(fmt.Errorf("parent error"))
+	child.Error(fmt.Errorf("child error"))
+
+	expected := "[test] parent error domain=example.com.br\n" +
+		"[test] child error domain=example.com.br zone=br\n"
+
+	if result := localBuffer.String(); result != expected {
+		t.Errorf("mismatch results. Expecting: “%s”; found “%s”", expected, result)
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	scenarios := []struct {
+		level    Level
+		expected string
+	}{
+		{level: LevelEmergency, expected: "emerg"},
+		{level: LevelAlert, expected: "alert"},
+		{level: LevelCritical, expected: "crit"},
+		{level: LevelError, expected: "err"},
+		{level: LevelWarning, expected: "warning"},
+		{level: LevelNotice, expected: "notice"},
+		{level: LevelInfo, expected: "info"},
+		{level: LevelDebug, expected: "debug"},
+		{level: Level(10), expected: "Level(10)"},
+	}
+
+	for i, scenario := range scenarios {
+		if result := scenario.level.String(); result != scenario.expected {
+			t.Errorf("scenario %d: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.expected, result)
+		}
+	}
+}
+
+func TestEntry_MarshalJSON(t *testing.T) {
+	scenarios := []struct {
+		description string
+		entry       Entry
+		expected    string
+	}{
+		{
+			description: "it should encode an entry correctly",
+			entry: Entry{
+				Time:       time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC),
+				Level:      LevelInfo,
+				Identifier: "test",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "this is a message",
+				Fields:     Fields{"zone": "br"},
+			},
+			expected: `{"time":"2017-01-02T15:04:05Z","level":"info","id":"test","file":"gostk/log/file.go","line":42,"message":"this is a message","fields":{"zone":"br"}}`,
+		},
+		{
+			description: "it should keep the original error details",
+			entry: Entry{
+				Time:    time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC),
+				Level:   LevelAlert,
+				Message: "gostk/log/file.go:42: failure detected",
+				Err: locatedError{
+					err:   fmt.Errorf("failure detected"),
+					file:  "gostk/log/file.go",
+					line:  42,
+					level: LevelAlert,
+				},
+			},
+			expected: `{"time":"2017-01-02T15:04:05Z","level":"alert","message":"gostk/log/file.go:42: failure detected","error":{"type":"log.locatedError","chain":["log.locatedError","*errors.errorString"],"file":"gostk/log/file.go","line":42,"level":"alert"}}`,
+		},
+		{
+			description: "it should use the default level for a simple error",
+			entry: Entry{
+				Time:    time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC),
+				Level:   LevelError,
+				Message: "failure detected",
+				Err:     fmt.Errorf("failure detected"),
+			},
+			expected: `{"time":"2017-01-02T15:04:05Z","level":"err","message":"failure detected","error":{"type":"*errors.errorString","level":"err"}}`,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result, err := json.Marshal(scenario.entry)
+		if err != nil {
+			t.Errorf("scenario %d, “%s”: unexpected error: %s", i, scenario.description, err)
+			continue
+		}
+
+		if string(result) != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package log
+
+// Hook processes the log entries before they are written to the syslog server,
+// the local logger and the sinks. Hooks are useful to attach custom behavior
+// to specific entries, like sending the errors of a type to an incident
+// tracker:
+//
+//    log.AddHook("incidents", log.HookFunc(func(e *log.Entry) bool {
+//      if _, ok := e.Err.(ErrDatabaseFailure); ok {
+//        tracker.Open(e.Message)
+//      }
+//      return true
+//    }))
+type Hook interface {
+	// Fire is called with every entry, in the order that the hooks were
+	// registered. The entry can be modified, and returning false drops it, so
+	// the next hooks and the destinations don't receive it. The Fields map is
+	// shared with the Logger, so it must be replaced and never changed in
+	// place.
+	Fire(e *Entry) bool
+}
+
+// HookFunc is an adapter to allow the use of ordinary functions as hooks.
+type HookFunc func(e *Entry) bool
+
+// Fire calls f(e).
+func (f HookFunc) Fire(e *Entry) bool {
+	return f(e)
+}
+
+// AddHook registers a hook in the default Core. The name replaces any hook
+// previously registered with the same name.
+func AddHook(name string, h Hook) {
+	std.AddHook(name, h)
+}
+
+// RemoveHook unregisters the hook identified by name from the default Core.
+func RemoveHook(name string) {
+	std.RemoveHook(name)
+}
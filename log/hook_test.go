@@ -0,0 +1,63 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/registrobr/gostk/runtime"
+)
+
+func TestCore_AddHook(t *testing.T) {
+	var localBuffer bytes.Buffer
+	core := NewCore()
+	core.SetLocalLogger(log.New(&localBuffer, "", 0))
+	core.SetLocalEncoder(TextEncoder{})
+
+	var entries []string
+	var errs []error
+	core.AddSink("test", mockSink{entries: &entries})
+
+	core.AddHook("drop", HookFunc(func(e *Entry) bool {
+		return e.Message != "secret"
+	}))
+	core.AddHook("errors", HookFunc(func(e *Entry) bool {
+		if e.Err != nil {
+			errs = append(errs, e.Err)
+		}
+		return true
+	}))
+	core.AddHook("mask", HookFunc(func(e *Entry) bool {
+		e.Fields = Fields{"masked": true}
+		return true
+	}))
+
+	err := fmt.Errorf("error detected")
+	l := core.NewLogger("test")
+	l.Info("secret")
+	l.Error(err)
+
+	core.RemoveHook("mask")
+	_, line, _ := runtime.Caller(0)
+	l.Info("message")
+
+	expected := "[test] error detected masked=true\n" +
+		fmt.Sprintf("[test] gostk/log/hook_test.go:%d: message\n", line+1)
+
+	if result := localBuffer.String(); result != expected {
+		t.Errorf("mismatch results. Expecting: “%s”; found “%s”", expected, result)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("mismatch sink entries. Expecting: 2; found “%v”", entries)
+	}
+
+	if len(errs) != 1 || errs[0] != err {
+		t.Errorf("mismatch hook errors. Expecting: “%v”; found “%v”", err, errs)
+	}
+
+	if m := core.Metrics(); m.Levels["info"] != 1 {
+		t.Errorf("dropped entry counted. Expecting: 1; found %d", m.Levels["info"])
+	}
+}
@@ -0,0 +1,41 @@
+package log
+
+// Lazy is a message that is only built when the entry is actually logged,
+// avoiding the formatting cost of expensive debug dumps when the level is
+// disabled or the entry is sampled out:
+//
+//    logger.Debug(log.Lazy(func() string {
+//      return dumpState(state)
+//    }))
+//
+// Lazy values can also be used as arguments of the formatted functions. Plain
+// func() string values are accepted as well and evaluated in the same way.
+type Lazy func() string
+
+// String builds the message.
+func (l Lazy) String() string {
+	return l()
+}
+
+// evaluate replaces the func() string arguments by their results. The original
+// slice is never modified, as it could belong to the caller.
+func evaluate(a []interface{}) []interface{} {
+	var evaluated []interface{}
+	for i, item := range a {
+		f, ok := item.(func() string)
+		if !ok {
+			continue
+		}
+
+		if evaluated == nil {
+			evaluated = make([]interface{}, len(a))
+			copy(evaluated, a)
+		}
+		evaluated[i] = f()
+	}
+
+	if evaluated == nil {
+		return a
+	}
+	return evaluated
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	scenarios := []struct {
+		description       string
+		level             Level
+		log               func(l Logger, message func() string)
+		expectedEvaluated bool
+		expected          string
+	}{
+		{
+			description: "it should evaluate a lazy message when logged",
+			level:       LevelDebug,
+			log: func(l Logger, message func() string) {
+				l.Debug(Lazy(message))
+			},
+			expectedEvaluated: true,
+			expected:          "expensive message",
+		},
+		{
+			description: "it should evaluate a function argument when logged",
+			level:       LevelDebug,
+			log: func(l Logger, message func() string) {
+				l.Debugf("dump: %s", message)
+			},
+			expectedEvaluated: true,
+			expected:          "dump: expensive message",
+		},
+		{
+			description: "it should not evaluate a lazy message when the level is disabled",
+			level:       LevelInfo,
+			log: func(l Logger, message func() string) {
+				l.Debug(Lazy(message))
+			},
+		},
+		{
+			description: "it should not evaluate a function argument when the level is disabled",
+			level:       LevelInfo,
+			log: func(l Logger, message func() string) {
+				l.Debugf("dump: %s", message)
+			},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var localBuffer bytes.Buffer
+		core := NewCore()
+		core.SetLocalLogger(log.New(&localBuffer, "", 0))
+		core.SetLevel(scenario.level)
+
+		evaluated := false
+		scenario.log(core.NewLogger("test"), func() string {
+			evaluated = true
+			return "expensive message"
+		})
+
+		if evaluated != scenario.expectedEvaluated {
+			t.Errorf("scenario %d, “%s”: mismatch evaluation. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedEvaluated, evaluated)
+		}
+
+		result := strings.TrimSpace(localBuffer.String())
+		if (scenario.expected == "" && result != "") || !strings.HasSuffix(result, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestLogger_Enabled(t *testing.T) {
+	core := NewCore()
+	core.SetLevel(LevelNotice)
+
+	l := core.NewLogger("test").(LevelEnabler)
+	for level := LevelEmergency; level <= LevelDebug; level++ {
+		if expected := level <= LevelNotice; l.Enabled(level) != expected {
+			t.Errorf("level “%s”: mismatch results. Expecting: “%v”", level, expected)
+		}
+	}
+
+	if !Enabled(LevelDebug) {
+		t.Error("the default core should log all levels")
+	}
+}
@@ -0,0 +1,179 @@
+package log
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// truncatedMarker is appended to the messages that were truncated, so the
+// reader knows that part of the content was lost.
+const truncatedMarker = "...[truncated]"
+
+// MaxMessageSizes defines the default maximum size, in bytes, of an encoded
+// message for each syslog network. UDP datagrams bigger than 1024 bytes may be
+// silently dropped by the syslog servers (RFC 3164, section 4.1), while stream
+// transports usually accept up to 8 KB (rsyslog default). Networks that aren't
+// listed have no limit.
+var MaxMessageSizes = map[string]int{
+	"":         8192,
+	"udp":      1024,
+	"udp4":     1024,
+	"udp6":     1024,
+	"unixgram": 8192,
+	"unix":     8192,
+	"tcp":      8192,
+	"tcp4":     8192,
+	"tcp6":     8192,
+}
+
+// SizeLimit defines how a log destination handles messages bigger than it
+// supports. The message is truncated in a valid UTF-8 boundary with a visible
+// marker or split into numbered continuation entries sharing a message id (the
+// fields "msgid" and "part").
+type SizeLimit struct {
+	// MaxSize is the maximum number of bytes of a message sent by the
+	// transport, including the header added by it (e.g. the syslog priority,
+	// timestamp, hostname and tag). When zero the default size of the
+	// transport is used, and a negative value disables the limit.
+	MaxSize int
+
+	// Split breaks the messages into continuation entries instead of
+	// truncating them.
+	Split bool
+}
+
+// write encodes the entry respecting the size limit, where defaultSize is the
+// limit of the transport and overhead is the size of the header added by it to
+// each message, and sends the result to f. The function is called more than
+// once when the entry is split, stopping on the first error. The message buffer
+// is reused, so it is valid only until f returns.
+func (s SizeLimit) write(enc Encoder, e Entry, defaultSize, overhead int, f func(msg []byte) error) error {
+	size := s.MaxSize
+	if size == 0 {
+		size = defaultSize
+	}
+
+	if size > 0 {
+		// keep at least one byte, as the content can't be completely lost
+		if size -= overhead; size < 1 {
+			size = 1
+		}
+	}
+
+	buf := buffers.Get().(*bytes.Buffer)
+	defer buffers.Put(buf)
+
+	encode := func(e Entry) []byte {
+		buf.Reset()
+		enc.Encode(buf, e)
+		return buf.Bytes()
+	}
+
+	msg := encode(e)
+	if size <= 0 || len(msg) <= size {
+		return f(msg)
+	}
+
+	if !s.Split {
+		n := fit(enc, e, e.Message, truncatedMarker, size)
+		e.Message = e.Message[:n] + truncatedMarker
+		return f(encode(e))
+	}
+
+	msgID := newMessageID()
+
+	for part, remaining := 1, e.Message; remaining != ""; part++ {
+		continuation := e
+		continuation.Fields = make(Fields, len(e.Fields)+2)
+		for key, value := range e.Fields {
+			continuation.Fields[key] = value
+		}
+		continuation.Fields["msgid"] = msgID
+		continuation.Fields["part"] = part
+
+		n := fit(enc, continuation, remaining, "", size)
+		if n == 0 {
+			// the size is too small even for a single character, so we accept
+			// an oversized message to avoid losing the content
+			_, n = utf8.DecodeRuneInString(remaining)
+		}
+
+		continuation.Message = remaining[:n]
+		if err := f(encode(continuation)); err != nil {
+			return err
+		}
+		remaining = remaining[n:]
+	}
+
+	return nil
+}
+
+// fit returns the biggest prefix length of text, in a valid UTF-8 boundary,
+// that followed by the suffix keeps the encoded entry inside the size limit.
+func fit(enc Encoder, e Entry, text, suffix string, size int) int {
+	low, high := 0, len(text)
+	for low < high {
+		middle := utf8Boundary(text, (low+high+1)/2)
+		if middle <= low {
+			// there's no rune boundary in the lower half, so check the next
+			// boundary directly
+			_, n := utf8.DecodeRuneInString(text[low:])
+			if middle = low + n; middle > high {
+				break
+			}
+		}
+
+		e.Message = text[:middle] + suffix
+		if encodedLen(enc, e) <= size {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+
+	return utf8Boundary(text, low)
+}
+
+// utf8Boundary moves the position n backwards until it reaches the beginning
+// of a rune.
+func utf8Boundary(text string, n int) int {
+	for n > 0 && n < len(text) && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return n
+}
+
+// syslogHeaderSize returns the maximum number of bytes added by the syslog
+// package to each message: the priority, the timestamp, the hostname (only
+// for remote servers), the tag with the process id and the final new line.
+//
+//    <191>2006-01-02T15:04:05-07:00 hostname tag[pid]: message\n
+func syslogHeaderSize(network, tag string) int {
+	if tag == "" {
+		tag = os.Args[0]
+	}
+
+	size := len("<191>") + len(tag) + len("[") + len(strconv.Itoa(os.Getpid())) + len("]: ") + len("\n")
+
+	if network == "" {
+		// the local syslog server receives the traditional timestamp and
+		// already knows the hostname
+		return size + len(time.Stamp) + len(" ")
+	}
+
+	hostname, _ := os.Hostname()
+	return size + len("2006-01-02T15:04:05-07:00") + len(" ") + len(hostname) + len(" ")
+}
+
+// newMessageID returns a random identifier shared by the continuation entries
+// of the same message.
+func newMessageID() string {
+	id := make([]byte, 4)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,137 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSizeLimit_write(t *testing.T) {
+	scenarios := []struct {
+		description string
+		limit       SizeLimit
+		defaultSize int
+		overhead    int
+		entry       Entry
+		expected    []string
+	}{
+		{
+			description: "it should keep a message inside the limit",
+			limit:       SizeLimit{MaxSize: 100},
+			entry:       Entry{Identifier: "test", Message: "this is a message"},
+			expected:    []string{"[test] this is a message"},
+		},
+		{
+			description: "it should ignore a disabled limit",
+			limit:       SizeLimit{MaxSize: -1},
+			defaultSize: 10,
+			entry:       Entry{Identifier: "test", Message: "this is a message"},
+			expected:    []string{"[test] this is a message"},
+		},
+		{
+			description: "it should truncate using the default size",
+			defaultSize: 25,
+			entry:       Entry{Identifier: "test", Message: "this is a big message"},
+			expected:    []string{"[test] this...[truncated]"},
+		},
+		{
+			description: "it should reserve the size of the transport header",
+			defaultSize: 40,
+			overhead:    15,
+			entry:       Entry{Identifier: "test", Message: "this is a big message"},
+			expected:    []string{"[test] this...[truncated]"},
+		},
+		{
+			description: "it should truncate in a valid UTF-8 boundary",
+			limit:       SizeLimit{MaxSize: 27},
+			entry:       Entry{Identifier: "test", Message: "ação→ação→ação"},
+			expected:    []string{"[test] ação...[truncated]"},
+		},
+		{
+			description: "it should split a message in continuation entries",
+			limit:       SizeLimit{MaxSize: 40, Split: true},
+			entry:       Entry{Identifier: "test", Message: "this is a big message that must be split"},
+			expected: []string{
+				"[test] this is a b msgid=ID part=1",
+				"[test] ig message  msgid=ID part=2",
+				"[test] that must b msgid=ID part=3",
+				"[test] e split msgid=ID part=4",
+			},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var msgs []string
+		scenario.limit.write(TextEncoder{}, scenario.entry, scenario.defaultSize, scenario.overhead, func(msg []byte) error {
+			msgs = append(msgs, string(msg))
+			return nil
+		})
+
+		for j, msg := range msgs {
+			if !utf8.ValidString(msg) {
+				t.Errorf("scenario %d, “%s”: invalid UTF-8 message “%q”", i, scenario.description, msg)
+			}
+
+			if index := strings.Index(msg, "msgid="); index >= 0 {
+				msgs[j] = msg[:index+6] + "ID" + msg[index+14:]
+			}
+		}
+
+		if !reflect.DeepEqual(msgs, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%#v”; found “%#v”",
+				i, scenario.description, scenario.expected, msgs)
+		}
+	}
+}
+
+func TestSyslogLimit(t *testing.T) {
+	var messages []string
+	core := NewCore()
+	core.syslog = mockSyslogWriter{
+		mockInfo: func(msg string) error {
+			messages = append(messages, msg)
+			return nil
+		},
+	}
+	core.syslogNetwork = "udp"
+	core.syslogHeader = syslogHeaderSize("udp", "app")
+
+	core.NewLogger("test").Info(strings.Repeat("a", 2000))
+
+	if len(messages) != 1 || len(messages[0])+core.syslogHeader > 1024 || !strings.HasSuffix(messages[0], truncatedMarker) {
+		t.Errorf("message not truncated to the UDP limit: %v", messages)
+	}
+}
+
+func TestSyslogHeaderSize(t *testing.T) {
+	hostname, _ := os.Hostname()
+	now := time.Now()
+
+	// headers written by the syslog package, with the biggest priority
+	scenarios := []struct {
+		description string
+		network     string
+		header      string
+	}{
+		{
+			description: "it should calculate the header of the local syslog server",
+			header:      fmt.Sprintf("<191>%s app[%d]: \n", now.Format(time.Stamp), os.Getpid()),
+		},
+		{
+			description: "it should calculate the header of a remote syslog server",
+			network:     "udp",
+			header:      fmt.Sprintf("<191>%s %s app[%d]: \n", now.Format("2006-01-02T15:04:05-07:00"), hostname, os.Getpid()),
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if size := syslogHeaderSize(scenario.network, "app"); size != len(scenario.header) {
+			t.Errorf("scenario %d, “%s”: mismatch sizes. Expecting: %d; found %d",
+				i, scenario.description, len(scenario.header), size)
+		}
+	}
+}
@@ -6,19 +6,24 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"log/syslog"
 	"os"
-	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/registrobr/gostk/path"
+	"github.com/registrobr/gostk/runtime"
 )
 
 // pathDeep defines the number of directories that are visible when logging a
 // message with the logging location.
 const pathDeep = 3
 
+// callerSkip is the number of stack frames between the location lookup and the
+// Logger method invoked by the user. Functions marked with runtime.Helper are
+// skipped automatically.
+const callerSkip = 2
+
 // Syslog level message, defined in RFC 5424, section 6.2.1
 const (
 	// LevelEmergency sets a high priority level of problem advising that system
@@ -80,10 +85,51 @@ var (
 //  corresponding log level.
 type Level int
 
+// String returns the syslog name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelEmergency:
+		return "emerg"
+	case LevelAlert:
+		return "alert"
+	case LevelCritical:
+		return "crit"
+	case LevelError:
+		return "err"
+	case LevelWarning:
+		return "warning"
+	case LevelNotice:
+		return "notice"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	}
+
+	return "Level(" + strconv.Itoa(int(l)) + ")"
+}
+
 type leveler interface {
 	Level() Level
 }
 
+// errorLevel walks the chain of wrapped errors returning the level of the first
+// error that has one. By default errors are logged with LevelError.
+func errorLevel(err error) Level {
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if levelError, ok := current.(leveler); ok {
+			return levelError.Level()
+		}
+	}
+	return LevelError
+}
+
+// fielder is implemented by errors that carry structured information, like the
+// ones from the gostk errors package.
+type fielder interface {
+	Fields() Fields
+}
+
 // syslogWriter is useful to mock a low level syslog writer for unit tests.
 type syslogWriter interface {
 	Close() error
@@ -97,14 +143,11 @@ type syslogWriter interface {
 	Debug(m string) (err error)
 }
 
-var (
-	// remoteLogger connection with a remote syslog server.
-	remoteLogger syslogWriter
-
-	// LocalLogger is the fallback log used when the remote logger isn't
-	// available.
-	LocalLogger *log.Logger
-)
+// LocalLogger is the fallback log of the default Core, used when the syslog
+// server isn't available. When it is replaced the local format is chosen again
+// for the new writer (see EncoderFromEnv), unless an encoder was defined with
+// SetLocalEncoder.
+var LocalLogger *log.Logger
 
 func init() {
 	LocalLogger = log.New(os.Stderr, "", log.LstdFlags)
@@ -115,51 +158,15 @@ func init() {
 // writer sends a log message with the given facility, severity and
 // tag. If network is empty, Dial will connect to the local syslog server. A
 // connection timeout defines how long it will wait for the connection until a
-// timeout error is raised.
+// timeout error is raised. The connection is used by the default Core.
 func Dial(network, raddr, tag string, timeout time.Duration) error {
-	// The channels has size of 1 (buffered) to avoid keeping an unnecessary goroutine blocked in
-	// memory. For example: a goroutine is spawn, and it returns via channel a new transaction or
-	// an error. After spawning a goroutine the program blocks in the select statement waiting
-	// until the first channel message. In case of a timeout message, the spawned goroutine will
-	// put a message in one of this two channels (ch and chErr) and simply returns (die), the
-	// program don't care about the messages, because it has already timed out. If the channels
-	// were not buffered the goroutine would be blocked trying to put a message into the channel
-	// until the program dies.
-	ch := make(chan *syslog.Writer, 1)
-	chErr := make(chan error, 1)
-
-	go func() {
-		w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
-		if err != nil {
-			chErr <- err
-			return
-		}
-
-		ch <- w
-	}()
-
-	select {
-	case remoteLogger = <-ch:
-		return nil
-	case err := <-chErr:
-		return err
-	case <-time.After(timeout):
-		return ErrDialTimeout
-	}
+	return std.Dial(network, raddr, tag, timeout)
 }
 
-// Close closes a connection to the syslog daemon. It is declared as a variable
-// to allow an easy mocking.
+// Close closes the default Core connection to the syslog daemon. It is declared
+// as a variable to allow an easy mocking.
 var Close = func() error {
-	if remoteLogger == nil {
-		return nil
-	}
-
-	err := remoteLogger.Close()
-	if err == nil {
-		remoteLogger = nil
-	}
-	return err
+	return std.Close()
 }
 
 // Logger allows logging messages in all different level types. As it is an
@@ -183,343 +190,322 @@ type Logger interface {
 	Debugf(m string, a ...interface{})
 
 	// SetCaller defines the number of invocations to follow-up to retrieve the
-	// actual caller of the log entry. For now is only used by the package easy
-	// functions.
+	// actual caller of the log entry.
+	//
+	// Deprecated: mark the wrapper functions with runtime.Helper instead, so
+	// they are skipped automatically when retrieving the caller.
 	SetCaller(n int)
 }
 
+// FieldLogger is a Logger that attaches fields to the messages, as the ones
+// returned by NewLogger. It is a separated interface, so the existing Logger
+// implementations keep working:
+//
+//    l := log.NewLogger(id).(log.FieldLogger).WithFields(log.Fields{"domain": fqdn})
+type FieldLogger interface {
+	Logger
+
+	// WithFields returns a new Logger that attaches the given fields to every
+	// message logged, keeping the fields already attached.
+	WithFields(fields Fields) FieldLogger
+}
+
+// LevelEnabler is implemented by the loggers that inform if a level would be
+// logged, as the ones returned by NewLogger. It is a separated interface, so the
+// existing Logger implementations keep working. Other loggers can fall back to
+// the package function Enabled:
+//
+//    enabled := log.Enabled(log.LevelDebug)
+//    if le, ok := l.(log.LevelEnabler); ok {
+//      enabled = le.Enabled(log.LevelDebug)
+//    }
+type LevelEnabler interface {
+	// Enabled checks if the messages of the level would be logged. Useful to
+	// avoid building expensive messages that would be ignored. The sampling is
+	// not considered, as its decision may change on every message.
+	Enabled(level Level) bool
+}
+
 type logger struct {
+	core       *Core
 	identifier string
 	caller     int
+	fields     Fields
 }
 
 // NewLogger returns a internal instance of the Logger type tagging an
 // identifier to every message logged. This identifier is useful to group many
-// messages to one related transaction id.
+// messages to one related transaction id. The Logger writes to the default
+// Core.
 var NewLogger = func(id string) Logger {
-	return &logger{
-		identifier: "[" + id + "] ",
-		caller:     3,
-	}
+	return std.NewLogger(id)
+}
+
+// defaultLogger is used by the package functions, so they don't create a new
+// Logger on every call. The package function is an extra frame between the
+// user and the Logger method.
+var defaultLogger = logger{
+	core:   std,
+	caller: callerSkip + 1,
 }
 
 func (l logger) Emerg(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Emerg
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelEmergency, a...)
 }
 
 func (l logger) Emergf(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Emerg
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelEmergency, m, a...)
 }
 
 func (l logger) Alert(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Alert
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelAlert, a...)
 }
 
 func (l logger) Alertf(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Alert
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelAlert, m, a...)
 }
 
 func (l logger) Crit(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Crit
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelCritical, a...)
 }
 
 func (l logger) Critf(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Crit
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelCritical, m, a...)
 }
 
 // Error converts an Go error into an error message. The responsibility of
 // knowing the file and line where the error occurred is from the Error()
-// function of the specific error.
+// function of the specific error. The fields carried by the error, or by an
+// error in its chain, are merged with the fields of the Logger.
 func (l logger) Error(e error) {
 	if e == nil {
 		return
 	}
 
-	msg := l.identifier + e.Error()
-	if remoteLogger == nil {
-		LocalLogger.Println(msg)
+	level := errorLevel(e)
+	if level < LevelEmergency || level > LevelDebug {
+		l.Warningf("Wrong error level: %d", level)
+		level = LevelError
+	}
+
+	if !l.core.enabled(level) || !l.core.sampled(level, l.identifier) {
 		return
 	}
 
-	var err error
-
-	if levelError, ok := e.(leveler); ok {
-		switch levelError.Level() {
-		case LevelEmergency:
-			err = remoteLogger.Emerg(msg)
-		case LevelAlert:
-			err = remoteLogger.Alert(msg)
-		case LevelCritical:
-			err = remoteLogger.Crit(msg)
-		case LevelError:
-			err = remoteLogger.Err(msg)
-		case LevelWarning:
-			err = remoteLogger.Warning(msg)
-		case LevelNotice:
-			err = remoteLogger.Notice(msg)
-		case LevelInfo:
-			err = remoteLogger.Info(msg)
-		case LevelDebug:
-			err = remoteLogger.Debug(msg)
-		default:
-			l.Warningf("Wrong error level: %d", levelError.Level())
-			err = remoteLogger.Err(msg)
+	fields := l.fields
+	for current := e; current != nil; current = errors.Unwrap(current) {
+		if errFields, ok := current.(fielder); ok {
+			fields = fields.merge(errFields.Fields())
+			break
 		}
-	} else {
-		err = remoteLogger.Err(msg)
 	}
 
-	if err != nil {
-		LocalLogger.Println("Error writing to syslog. Details:", err)
-		LocalLogger.Println(msg)
-	}
+	l.core.write(Entry{
+		Time:       time.Now(),
+		Level:      level,
+		Identifier: l.identifier,
+		Message:    e.Error(),
+		Fields:     fields,
+		Err:        e,
+	})
 }
 
 func (l logger) Errorf(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Err
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelError, m, a...)
 }
 
 func (l logger) Warning(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Warning
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelWarning, a...)
 }
 
 func (l logger) Warningf(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Warning
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelWarning, m, a...)
 }
 
 func (l logger) Notice(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Notice
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelNotice, a...)
 }
 
 func (l logger) Noticef(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Notice
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelNotice, m, a...)
 }
 
 func (l logger) Info(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Info
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelInfo, a...)
 }
 
 func (l logger) Infof(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Info
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelInfo, m, a...)
 }
 
 func (l logger) Debug(a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Debug
-	}
-	l.logWithSourceInfo(f, a...)
+	l.logWithSourceInfo(LevelDebug, a...)
 }
 
 func (l logger) Debugf(m string, a ...interface{}) {
-	var f logFunc
-	if remoteLogger != nil {
-		f = remoteLogger.Debug
-	}
-	l.logWithSourceInfof(f, m, a...)
+	l.logWithSourceInfof(LevelDebug, m, a...)
+}
+
+func (l logger) Enabled(level Level) bool {
+	return l.core.enabled(level)
+}
+
+func (l logger) WithFields(fields Fields) FieldLogger {
+	l.fields = l.fields.merge(fields)
+	return &l
 }
 
 func (l *logger) SetCaller(n int) {
 	l.caller = n
 }
 
+// Enabled checks if the messages of the level would be logged by the package
+// functions.
+func Enabled(level Level) bool {
+	return std.enabled(level)
+}
+
 // Emerg log an emergency message
 func Emerg(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Emerg(a...)
+	defaultLogger.Emerg(a...)
 }
 
 // Emergf log an emergency message with arguments
 func Emergf(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Emergf(m, a...)
+	defaultLogger.Emergf(m, a...)
 }
 
 // Alert log an emergency message
 func Alert(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Alert(a...)
+	defaultLogger.Alert(a...)
 }
 
 // Alertf log an emergency message with arguments
 func Alertf(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Alertf(m, a...)
+	defaultLogger.Alertf(m, a...)
 }
 
 // Crit log an emergency message
 func Crit(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Crit(a...)
+	defaultLogger.Crit(a...)
 }
 
 // Critf log an emergency message with arguments
 func Critf(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Critf(m, a...)
+	defaultLogger.Critf(m, a...)
 }
 
 // Error log an emergency message
 func Error(err error) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Error(err)
+	defaultLogger.Error(err)
 }
 
 // Errorf log an emergency message with arguments
 func Errorf(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Errorf(m, a...)
+	defaultLogger.Errorf(m, a...)
 }
 
 // Warning log an emergency message
 func Warning(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Warning(a...)
+	defaultLogger.Warning(a...)
 }
 
 // Warningf log an emergency message with arguments
 func Warningf(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Warningf(m, a...)
+	defaultLogger.Warningf(m, a...)
 }
 
 // Notice log an emergency message
 func Notice(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Notice(a...)
+	defaultLogger.Notice(a...)
 }
 
 // Noticef log an emergency message with arguments
 func Noticef(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Noticef(m, a...)
+	defaultLogger.Noticef(m, a...)
 }
 
 // Info log an emergency message
 func Info(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Info(a...)
+	defaultLogger.Info(a...)
 }
 
 // Infof log an emergency message with arguments
 func Infof(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Infof(m, a...)
+	defaultLogger.Infof(m, a...)
 }
 
 // Debug log an emergency message
 func Debug(a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Debug(a...)
+	defaultLogger.Debug(a...)
 }
 
 // Debugf log an emergency message with arguments
 func Debugf(m string, a ...interface{}) {
-	l := NewLogger("")
-	l.SetCaller(4)
-	l.Debugf(m, a...)
+	defaultLogger.Debugf(m, a...)
 }
 
 type logFunc func(string) error
 
-func (l logger) logWithSourceInfo(f logFunc, a ...interface{}) {
-	// identify the caller from 3 levels above, as this function is never called
-	// directly from the place that logged the message
-	_, file, line, _ := runtime.Caller(l.caller)
+// sprint works like fmt.Sprint, but returns a single string argument directly,
+// avoiding the allocation in the most common case.
+func sprint(a []interface{}) string {
+	if len(a) == 1 {
+		if s, ok := a[0].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(evaluate(a)...)
+}
+
+func (l logger) logWithSourceInfo(level Level, a ...interface{}) {
+	if !l.core.enabled(level) || !l.core.sampled(level, l.identifier) {
+		return
+	}
+
+	// this function is never called directly from the place that logged the
+	// message, so the log package frames and the helper functions are skipped
+	file, line, _ := runtime.Caller(l.caller)
 	file = path.RelevantPath(file, pathDeep)
-	doLog(f, l.identifier, fmt.Sprint(a...), file, line)
+	l.doLog(level, sprint(a), file, line)
 }
 
-func (l logger) logWithSourceInfof(f logFunc, message string, a ...interface{}) {
-	// identify the caller from 3 levels above, as this function is never called
-	// directly from the place that logged the message
-	_, file, line, _ := runtime.Caller(l.caller)
+func (l logger) logWithSourceInfof(level Level, message string, a ...interface{}) {
+	if !l.core.enabled(level) || !l.core.sampled(level, l.identifier) {
+		return
+	}
+
+	// this function is never called directly from the place that logged the
+	// message, so the log package frames and the helper functions are skipped
+	file, line, _ := runtime.Caller(l.caller)
 	file = path.RelevantPath(file, pathDeep)
-	doLog(f, l.identifier, fmt.Sprintf(message, a...), file, line)
+	l.doLog(level, fmt.Sprintf(message, evaluate(a)...), file, line)
 }
 
-func doLog(f logFunc, prefix, message, file string, line int) {
+func (l logger) doLog(level Level, message, file string, line int) {
+	now := time.Now()
+
 	// support multiline log message, breaking it in many log entries
-	for _, item := range strings.Split(message, "\n") {
+	for message != "" {
+		item := message
+		if i := strings.IndexByte(message, '\n'); i >= 0 {
+			item, message = message[:i], message[i+1:]
+		} else {
+			message = ""
+		}
+
 		if item == "" {
 			continue
 		}
 
-		msg := fmt.Sprintf("%s%s:%d: %s", prefix, file, line, item)
-
-		if f == nil {
-			LocalLogger.Println(msg)
-
-		} else if err := f(msg); err != nil {
-			LocalLogger.Println("Error writing to syslog. Details:", err)
-			LocalLogger.Println(msg)
-		}
+		l.core.write(Entry{
+			Time:       now,
+			Level:      level,
+			Identifier: l.identifier,
+			File:       file,
+			Line:       line,
+			Message:    item,
+			Fields:     l.fields,
+		})
 	}
 }
@@ -6,9 +6,12 @@ import (
 	"log"
 	"net"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/registrobr/gostk/runtime"
 )
 
 func TestDial(t *testing.T) {
@@ -96,13 +99,13 @@ func TestClose(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	for i, scenario := range scenarios {
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		if err := Close(); !reflect.DeepEqual(err, scenario.expectedError) {
 			t.Errorf("scenario %d, “%s”: mismatch errors. Expecting: “%v”; found “%v”",
@@ -162,9 +165,9 @@ func TestLogger_Emerg(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -172,7 +175,7 @@ func TestLogger_Emerg(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Emerg(scenario.msg)
@@ -240,9 +243,9 @@ func TestLogger_Emergf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -250,7 +253,7 @@ func TestLogger_Emergf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Emergf(scenario.msg, scenario.arguments...)
@@ -315,9 +318,9 @@ func TestLogger_Alert(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -325,7 +328,7 @@ func TestLogger_Alert(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Alert(scenario.msg)
@@ -393,9 +396,9 @@ func TestLogger_Alertf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -403,7 +406,7 @@ func TestLogger_Alertf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Alertf(scenario.msg, scenario.arguments...)
@@ -468,9 +471,9 @@ func TestLogger_Crit(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -478,7 +481,7 @@ func TestLogger_Crit(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Crit(scenario.msg)
@@ -546,9 +549,9 @@ func TestLogger_Critf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -556,7 +559,7 @@ func TestLogger_Critf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Critf(scenario.msg, scenario.arguments...)
@@ -683,6 +686,23 @@ func TestLogger_Error(t *testing.T) {
 			},
 			identifier: "test",
 		},
+		{
+			description: "it should use the level of a wrapped error",
+			err: fmt.Errorf("loading the domain: %w", levelError{
+				msg:   "error message",
+				level: LevelCritical,
+			}),
+			remoteLogger: mockSyslogWriter{
+				mockCrit: func(msg string) error {
+					expectedMsg := "[test] loading the domain: error message"
+					if msg != expectedMsg {
+						t.Errorf("mismatch message. Expecting “%s”; found “%s”", expectedMsg, msg)
+					}
+					return nil
+				},
+			},
+			identifier: "test",
+		},
 		{
 			description: "it should log correctly an error message",
 			err: levelError{
@@ -824,9 +844,9 @@ func TestLogger_Error(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -834,7 +854,7 @@ func TestLogger_Error(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Error(scenario.err)
@@ -902,9 +922,9 @@ func TestLogger_Errorf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -912,7 +932,7 @@ func TestLogger_Errorf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Errorf(scenario.msg, scenario.arguments...)
@@ -977,9 +997,9 @@ func TestLogger_Warning(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -987,7 +1007,7 @@ func TestLogger_Warning(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Warning(scenario.msg)
@@ -1055,9 +1075,9 @@ func TestLogger_Warningf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1065,7 +1085,7 @@ func TestLogger_Warningf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Warningf(scenario.msg, scenario.arguments...)
@@ -1130,9 +1150,9 @@ func TestLogger_Notice(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1140,7 +1160,7 @@ func TestLogger_Notice(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Notice(scenario.msg)
@@ -1208,9 +1228,9 @@ func TestLogger_Noticef(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1218,7 +1238,7 @@ func TestLogger_Noticef(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Noticef(scenario.msg, scenario.arguments...)
@@ -1283,9 +1303,9 @@ func TestLogger_Info(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1293,7 +1313,7 @@ func TestLogger_Info(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Info(scenario.msg)
@@ -1361,9 +1381,9 @@ func TestLogger_Infof(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1371,7 +1391,7 @@ func TestLogger_Infof(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Infof(scenario.msg, scenario.arguments...)
@@ -1436,9 +1456,9 @@ func TestLogger_Debug(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1446,7 +1466,7 @@ func TestLogger_Debug(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Debug(scenario.msg)
@@ -1514,9 +1534,9 @@ func TestLogger_Debugf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1524,7 +1544,7 @@ func TestLogger_Debugf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		logger := NewLogger(scenario.identifier)
 		logger.Debugf(scenario.msg, scenario.arguments...)
@@ -1580,9 +1600,9 @@ func TestEmerg(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1590,7 +1610,7 @@ func TestEmerg(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Emerg(scenario.msg)
 
@@ -1648,9 +1668,9 @@ func TestEmergf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1658,7 +1678,7 @@ func TestEmergf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Emergf(scenario.msg, scenario.arguments...)
 
@@ -1713,9 +1733,9 @@ func TestAlert(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1723,7 +1743,7 @@ func TestAlert(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Alert(scenario.msg)
 
@@ -1781,9 +1801,9 @@ func TestAlertf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1791,7 +1811,7 @@ func TestAlertf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Alertf(scenario.msg, scenario.arguments...)
 
@@ -1846,9 +1866,9 @@ func TestCrit(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1856,7 +1876,7 @@ func TestCrit(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Crit(scenario.msg)
 
@@ -1914,9 +1934,9 @@ func TestCritf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -1924,7 +1944,7 @@ func TestCritf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Critf(scenario.msg, scenario.arguments...)
 
@@ -2129,9 +2149,9 @@ func TestError(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2139,7 +2159,7 @@ func TestError(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Error(scenario.err)
 
@@ -2197,9 +2217,9 @@ func TestErrorf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2207,7 +2227,7 @@ func TestErrorf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Errorf(scenario.msg, scenario.arguments...)
 
@@ -2262,9 +2282,9 @@ func TestWarning(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2272,7 +2292,7 @@ func TestWarning(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Warning(scenario.msg)
 
@@ -2330,9 +2350,9 @@ func TestWarningf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2340,7 +2360,7 @@ func TestWarningf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Warningf(scenario.msg, scenario.arguments...)
 
@@ -2395,9 +2415,9 @@ func TestNotice(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2405,7 +2425,7 @@ func TestNotice(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Notice(scenario.msg)
 
@@ -2463,9 +2483,9 @@ func TestNoticef(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2473,7 +2493,7 @@ func TestNoticef(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Noticef(scenario.msg, scenario.arguments...)
 
@@ -2528,9 +2548,9 @@ func TestInfo(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2538,7 +2558,7 @@ func TestInfo(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Info(scenario.msg)
 
@@ -2596,9 +2616,9 @@ func TestInfof(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2606,7 +2626,7 @@ func TestInfof(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Infof(scenario.msg, scenario.arguments...)
 
@@ -2661,9 +2681,9 @@ func TestDebug(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2671,7 +2691,7 @@ func TestDebug(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Debug(scenario.msg)
 
@@ -2729,9 +2749,9 @@ func TestDebugf(t *testing.T) {
 		},
 	}
 
-	originalRemoteLogger := remoteLogger
+	originalRemoteLogger := std.syslog
 	defer func() {
-		remoteLogger = originalRemoteLogger
+		std.syslog = originalRemoteLogger
 	}()
 
 	var localBuffer bytes.Buffer
@@ -2739,7 +2759,7 @@ func TestDebugf(t *testing.T) {
 
 	for i, scenario := range scenarios {
 		localBuffer.Reset()
-		remoteLogger = scenario.remoteLogger
+		std.syslog = scenario.remoteLogger
 
 		Debugf(scenario.msg, scenario.arguments...)
 
@@ -2752,6 +2772,68 @@ func TestDebugf(t *testing.T) {
 	}
 }
 
+func TestLogger_caller(t *testing.T) {
+	scenarios := []struct {
+		description string
+		log         func() (line int)
+		expected    string
+	}{
+		{
+			description: "it should identify the caller of a logger method",
+			log: func() int {
+				_, line, _ := runtime.Caller(0)
+				NewLogger("test").Info("this is a message")
+				return line + 1
+			},
+			expected: `^\[test\] gostk/log/log_test.go:%d: this is a message$`,
+		},
+		{
+			description: "it should identify the caller of a package function",
+			log: func() int {
+				_, line, _ := runtime.Caller(0)
+				Info("this is a message")
+				return line + 1
+			},
+			expected: `^\[\] gostk/log/log_test.go:%d: this is a message$`,
+		},
+		{
+			description: "it should skip the helper functions",
+			log: func() int {
+				_, line, _ := runtime.Caller(0)
+				helperInfof("this is a %s", "message")
+				return line + 1
+			},
+			expected: `^\[test\] gostk/log/log_test.go:%d: this is a message$`,
+		},
+	}
+
+	originalRemoteLogger := std.syslog
+	defer func() {
+		std.syslog = originalRemoteLogger
+	}()
+
+	var localBuffer bytes.Buffer
+	LocalLogger = log.New(&localBuffer, "", 0)
+	std.syslog = nil
+
+	for i, scenario := range scenarios {
+		localBuffer.Reset()
+		expected := regexp.MustCompile(fmt.Sprintf(scenario.expected, scenario.log()))
+
+		localMessage := strings.TrimSpace(localBuffer.String())
+		if !expected.MatchString(localMessage) {
+			t.Errorf("scenario %d, “%s”: mismatch message. Expecting “%s”; found “%s”",
+				i, scenario.description, expected.String(), localMessage,
+			)
+		}
+	}
+}
+
+func helperInfof(m string, a ...interface{}) {
+	runtime.Helper()
+	NewLogger("test").Infof(m, a...)
+}
+
 type mockSyslogWriter struct {
 	mockClose   func() error
 	mockEmerg   func(msg string) (err error)
@@ -0,0 +1,145 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// logfmtTimeFormat is the layout used to write the entry time.
+const logfmtTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// LogfmtEncoder encodes the log entries in the logfmt format, where each entry
+// is a sequence of key=value pairs, friendly to log aggregators:
+//
+//    ts=2017-01-02T15:04:05.000Z level=info id=abc caller=gostk/log/file.go:42 msg="a message" zone=br
+//
+// The identifier and the caller are omitted when they are unknown. Entries with
+// the original error also have the keys error.type, error.chain (types
+// separated by ">"), error.file, error.line, error.level and error.stack
+// (locations separated by ">"), when the stack was captured. Values with
+// spaces, quotes, equal signs or control characters are quoted and escaped.
+type LogfmtEncoder struct{}
+
+// Encode writes the logfmt representation of the entry in the buffer.
+func (LogfmtEncoder) Encode(buf *bytes.Buffer, e Entry) {
+	buf.WriteString("ts=")
+	buf.WriteString(e.Time.Format(logfmtTimeFormat))
+
+	buf.WriteString(" level=")
+	buf.WriteString(e.Level.String())
+
+	if e.Identifier != "" {
+		buf.WriteString(" id=")
+		writeLogfmtValue(buf, e.Identifier)
+	}
+
+	if e.File != "" {
+		buf.WriteString(" caller=")
+		writeLogfmtValue(buf, e.File+":"+strconv.Itoa(e.Line))
+	}
+
+	buf.WriteString(" msg=")
+	writeLogfmtValue(buf, e.Message)
+
+	if details := NewErrorDetails(e.Err); details != nil {
+		buf.WriteString(" error.type=")
+		writeLogfmtValue(buf, details.Type)
+
+		if len(details.Chain) > 0 {
+			buf.WriteString(" error.chain=")
+			writeLogfmtValue(buf, strings.Join(details.Chain, ">"))
+		}
+
+		if details.File != "" {
+			buf.WriteString(" error.file=")
+			writeLogfmtValue(buf, details.File)
+			buf.WriteString(" error.line=")
+			buf.WriteString(strconv.Itoa(details.Line))
+		}
+
+		buf.WriteString(" error.level=")
+		buf.WriteString(details.Level)
+
+		if len(details.Stack) > 0 {
+			locations := make([]string, len(details.Stack))
+			for i, frame := range details.Stack {
+				locations[i] = frame.File + ":" + strconv.Itoa(frame.Line)
+			}
+
+			buf.WriteString(" error.stack=")
+			writeLogfmtValue(buf, strings.Join(locations, ">"))
+		}
+	}
+
+	for _, key := range e.Fields.keys() {
+		buf.WriteByte(' ')
+		writeLogfmtKey(buf, key)
+		buf.WriteByte('=')
+		writeLogfmtValue(buf, fmt.Sprint(e.Fields[key]))
+	}
+}
+
+// writeLogfmtKey writes the key replacing the characters that aren't allowed
+// in a logfmt key by underscores.
+func writeLogfmtKey(buf *bytes.Buffer, key string) {
+	if key == "" {
+		buf.WriteByte('_')
+		return
+	}
+
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			buf.WriteByte('_')
+			continue
+		}
+		buf.WriteRune(r)
+	}
+}
+
+// writeLogfmtValue writes the value, quoting it when necessary.
+func writeLogfmtValue(buf *bytes.Buffer, value string) {
+	if !logfmtNeedsQuote(value) {
+		buf.WriteString(value)
+		return
+	}
+
+	buf.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < ' ' || r == 0x7f {
+				fmt.Fprintf(buf, `\u%04x`, r)
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// logfmtNeedsQuote checks if the value contains any character that would break
+// the key=value parsing.
+func logfmtNeedsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
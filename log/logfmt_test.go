@@ -0,0 +1,159 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/registrobr/gostk/runtime"
+)
+
+func TestLogfmtEncoder_Encode(t *testing.T) {
+	now := time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	scenarios := []struct {
+		description string
+		entry       Entry
+		expected    string
+	}{
+		{
+			description: "it should encode an entry correctly",
+			entry: Entry{
+				Time:       now,
+				Level:      LevelInfo,
+				Identifier: "abc",
+				File:       "gostk/log/file.go",
+				Line:       42,
+				Message:    "message",
+			},
+			expected: "ts=2017-01-02T15:04:05.000Z level=info id=abc caller=gostk/log/file.go:42 msg=message",
+		},
+		{
+			description: "it should omit the unknown identifier and location",
+			entry: Entry{
+				Time:    now,
+				Level:   LevelError,
+				Message: "this is an error",
+			},
+			expected: `ts=2017-01-02T15:04:05.000Z level=err msg="this is an error"`,
+		},
+		{
+			description: "it should escape quotes, backslashes and control characters",
+			entry: Entry{
+				Time:    now,
+				Level:   LevelDebug,
+				Message: "a \"quoted\" C:\\path\nwith\ttab\x00",
+			},
+			expected: `ts=2017-01-02T15:04:05.000Z level=debug msg="a \"quoted\" C:\\path\nwith\ttab\u0000"`,
+		},
+		{
+			description: "it should encode the fields correctly",
+			entry: Entry{
+				Time:    now,
+				Level:   LevelNotice,
+				Message: "message",
+				Fields: Fields{
+					"zone":       "br",
+					"empty":      "",
+					"equal":      "a=b",
+					"with space": 10,
+					"error":      fmt.Errorf("failure detected"),
+				},
+			},
+			expected: `ts=2017-01-02T15:04:05.000Z level=notice msg=message empty="" equal="a=b" error="failure detected" with_space=10 zone=br`,
+		},
+		{
+			description: "it should encode the original error details",
+			entry: Entry{
+				Time:    now,
+				Level:   LevelCritical,
+				Message: "gostk/log/file.go:42: failure detected",
+				Err: locatedError{
+					err:   fmt.Errorf("wrapped: %w", levelError{msg: "failure detected", level: LevelWarning}),
+					file:  "gostk/log/file.go",
+					line:  42,
+					level: LevelCritical,
+				},
+			},
+			expected: `ts=2017-01-02T15:04:05.000Z level=crit msg="gostk/log/file.go:42: failure detected" error.type=log.locatedError error.chain=log.locatedError>*fmt.wrapError>log.levelError error.file=gostk/log/file.go error.line=42 error.level=crit`,
+		},
+		{
+			description: "it should encode the error stack",
+			entry: Entry{
+				Time:    now,
+				Level:   LevelCritical,
+				Message: "failure detected",
+				Err: locatedError{
+					err:   fmt.Errorf("failure detected"),
+					level: LevelCritical,
+					frames: []runtime.Frame{
+						{Function: "main.handle", File: "/home/user/go/src/project/handler/handler.go", Line: 42},
+						{Function: "main.main", File: "/home/user/go/src/project/main.go", Line: 10},
+					},
+				},
+			},
+			expected: `ts=2017-01-02T15:04:05.000Z level=crit msg="failure detected" error.type=log.locatedError error.chain=log.locatedError>*errors.errorString error.level=crit error.stack=project/handler/handler.go:42>src/project/main.go:10`,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		var buf bytes.Buffer
+		LogfmtEncoder{}.Encode(&buf, scenario.entry)
+
+		if result := buf.String(); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%s”; found “%s”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestSyslogEncoder(t *testing.T) {
+	var messages []string
+	core := NewCore()
+	core.syslog = mockSyslogWriter{
+		mockInfo: func(msg string) error {
+			messages = append(messages, msg)
+			return nil
+		},
+	}
+	core.SetSyslogEncoder(LogfmtEncoder{})
+
+	core.NewLogger("abc").(FieldLogger).WithFields(Fields{"zone": "br"}).Info("this is a message")
+
+	expected := regexp.MustCompile(`^ts=\S+ level=info id=abc caller=gostk/log/logfmt_test.go:[0-9]+ msg="this is a message" zone=br$`)
+	if len(messages) != 1 || !expected.MatchString(messages[0]) {
+		t.Errorf("mismatch results. Expecting: “%s”; found “%v”", expected.String(), messages)
+	}
+}
+
+// locatedError simulates the errors package, that stores the location and the
+// level of the error.
+type locatedError struct {
+	err    error
+	file   string
+	line   int
+	level  Level
+	frames []runtime.Frame
+}
+
+func (e locatedError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.file, e.line, e.err)
+}
+
+func (e locatedError) Unwrap() error {
+	return e.err
+}
+
+func (e locatedError) Location() (string, int) {
+	return e.file, e.line
+}
+
+func (e locatedError) Level() Level {
+	return e.level
+}
+
+func (e locatedError) Frames() []runtime.Frame {
+	return e.frames
+}
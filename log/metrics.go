@@ -0,0 +1,230 @@
+package log
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Sink names used in the metrics.
+const (
+	sinkSyslog = "syslog"
+	sinkLocal  = "local"
+)
+
+// Metrics stores a snapshot of the log counters.
+type Metrics struct {
+	// Levels is the number of entries logged by level name.
+	Levels map[string]uint64
+
+	// Sinks is the number of writes by destination (syslog, local, ...).
+	Sinks map[string]SinkMetrics
+
+	// Dropped is the number of entries lost by a destination without other
+	// fallback: failures of the local logger, of the sinks and of the webhook
+	// deliveries.
+	Dropped uint64
+
+	// Sampled is the number of entries ignored by the sampler.
+	Sampled uint64
+
+	// Dials is the number of successful calls to Dial, including the first
+	// connection. The reconnections made internally by the log/syslog writer
+	// after a write failure aren't counted; use the syslog failures in Sinks
+	// to detect an unstable server.
+	Dials uint64
+}
+
+// SinkMetrics stores the number of successful and failed writes in a log
+// destination.
+type SinkMetrics struct {
+	Success uint64
+	Failure uint64
+}
+
+// counters stores the live values of the metrics. Level and global counters
+// are updated atomically, while the sinks map is protected by the mutex.
+type counters struct {
+	levels  [LevelDebug + 1]uint64
+	dropped uint64
+	sampled uint64
+	dials   uint64
+
+	mutex sync.RWMutex
+	sinks map[string]*SinkMetrics
+}
+
+func (c *counters) countLevel(level Level) {
+	if level >= LevelEmergency && level <= LevelDebug {
+		atomic.AddUint64(&c.levels[level], 1)
+	}
+}
+
+func (c *counters) countDropped() {
+	atomic.AddUint64(&c.dropped, 1)
+}
+
+func (c *counters) countSampled() {
+	atomic.AddUint64(&c.sampled, 1)
+}
+
+func (c *counters) countDial() {
+	atomic.AddUint64(&c.dials, 1)
+}
+
+func (c *counters) countSink(sink string, err error) {
+	c.mutex.RLock()
+	m, ok := c.sinks[sink]
+	c.mutex.RUnlock()
+
+	if !ok {
+		c.mutex.Lock()
+		if m, ok = c.sinks[sink]; !ok {
+			if c.sinks == nil {
+				c.sinks = make(map[string]*SinkMetrics)
+			}
+			m = new(SinkMetrics)
+			c.sinks[sink] = m
+		}
+		c.mutex.Unlock()
+	}
+
+	if err == nil {
+		atomic.AddUint64(&m.Success, 1)
+	} else {
+		atomic.AddUint64(&m.Failure, 1)
+	}
+}
+
+func (c *counters) snapshot() Metrics {
+	m := Metrics{
+		Levels:  make(map[string]uint64),
+		Sinks:   make(map[string]SinkMetrics),
+		Dropped: atomic.LoadUint64(&c.dropped),
+		Sampled: atomic.LoadUint64(&c.sampled),
+		Dials:   atomic.LoadUint64(&c.dials),
+	}
+
+	for level := LevelEmergency; level <= LevelDebug; level++ {
+		m.Levels[level.String()] = atomic.LoadUint64(&c.levels[level])
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for sink, sinkMetrics := range c.sinks {
+		m.Sinks[sink] = SinkMetrics{
+			Success: atomic.LoadUint64(&sinkMetrics.Success),
+			Failure: atomic.LoadUint64(&sinkMetrics.Failure),
+		}
+	}
+
+	return m
+}
+
+func (c *counters) reset() {
+	for level := range c.levels {
+		atomic.StoreUint64(&c.levels[level], 0)
+	}
+	atomic.StoreUint64(&c.dropped, 0)
+	atomic.StoreUint64(&c.sampled, 0)
+	atomic.StoreUint64(&c.dials, 0)
+
+	c.mutex.Lock()
+	c.sinks = nil
+	c.mutex.Unlock()
+}
+
+// Metrics returns a snapshot of the Core counters.
+func (c *Core) Metrics() Metrics {
+	return c.counters.snapshot()
+}
+
+// ResetMetrics sets all the Core counters to zero.
+func (c *Core) ResetMetrics() {
+	c.counters.reset()
+}
+
+// CurrentMetrics returns a snapshot of the default Core counters.
+func CurrentMetrics() Metrics {
+	return std.Metrics()
+}
+
+// ResetMetrics sets all the default Core counters to zero.
+func ResetMetrics() {
+	std.ResetMetrics()
+}
+
+// PublishExpvar exports the default Core counters with expvar under the given
+// name, so they are available in the /debug/vars HTTP endpoint. As any expvar
+// variable, it panics if the name is already registered.
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return CurrentMetrics()
+	}))
+}
+
+// WritePrometheus writes the default Core counters in the Prometheus text
+// exposition format.
+func WritePrometheus(w io.Writer) error {
+	return std.WritePrometheus(w)
+}
+
+// WritePrometheus writes the Core counters in the Prometheus text exposition
+// format.
+func (c *Core) WritePrometheus(w io.Writer) error {
+	m := c.Metrics()
+
+	var err error
+	printf := func(format string, a ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, a...)
+		}
+	}
+
+	printf("# HELP gostk_log_entries_total Number of log entries by level.\n")
+	printf("# TYPE gostk_log_entries_total counter\n")
+	for level := LevelEmergency; level <= LevelDebug; level++ {
+		printf("gostk_log_entries_total{level=%q} %d\n", level.String(), m.Levels[level.String()])
+	}
+
+	sinks := make([]string, 0, len(m.Sinks))
+	for sink := range m.Sinks {
+		sinks = append(sinks, sink)
+	}
+	sort.Strings(sinks)
+
+	printf("# HELP gostk_log_sink_writes_total Number of writes by log destination and result.\n")
+	printf("# TYPE gostk_log_sink_writes_total counter\n")
+	for _, sink := range sinks {
+		printf("gostk_log_sink_writes_total{sink=%q,result=\"success\"} %d\n", sink, m.Sinks[sink].Success)
+		printf("gostk_log_sink_writes_total{sink=%q,result=\"failure\"} %d\n", sink, m.Sinks[sink].Failure)
+	}
+
+	printf("# HELP gostk_log_dropped_total Number of log entries lost by a destination.\n")
+	printf("# TYPE gostk_log_dropped_total counter\n")
+	printf("gostk_log_dropped_total %d\n", m.Dropped)
+
+	printf("# HELP gostk_log_sampled_total Number of log entries ignored by the sampler.\n")
+	printf("# TYPE gostk_log_sampled_total counter\n")
+	printf("gostk_log_sampled_total %d\n", m.Sampled)
+
+	printf("# HELP gostk_log_dials_total Number of successful calls to Dial, including the first connection.\n")
+	printf("# TYPE gostk_log_dials_total counter\n")
+	printf("gostk_log_dials_total %d\n", m.Dials)
+
+	return err
+}
+
+// PrometheusHandler returns an HTTP handler that exposes the default Core
+// counters in the Prometheus text exposition format.
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WritePrometheus(w)
+	})
+}
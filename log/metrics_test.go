@@ -0,0 +1,105 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCore_Metrics(t *testing.T) {
+	var localBuffer bytes.Buffer
+	core := NewCore()
+	core.SetLocalLogger(log.New(&localBuffer, "", 0))
+
+	core.syslog = mockSyslogWriter{
+		mockErr: func(msg string) error {
+			return nil
+		},
+		mockCrit: func(msg string) error {
+			return fmt.Errorf("error detected")
+		},
+	}
+
+	core.NewLogger("test").Errorf("this is the message 1\nthis is the message 2")
+	core.NewLogger("test").Crit("this is a message")
+
+	core.syslog = nil
+	core.NewLogger("test").Info("this is a message")
+
+	core.SetLocalLogger(log.New(failWriter{}, "", 0))
+	core.NewLogger("test").Info("this is a message")
+
+	var sinkEntries []string
+	core.SetLocalLogger(log.New(&localBuffer, "", 0))
+	core.AddSink("broken", mockSink{entries: &sinkEntries, err: fmt.Errorf("error detected")})
+	core.NewLogger("test").Info("this is a message")
+
+	core.counters.countDial()
+	core.counters.countDial()
+
+	expected := Metrics{
+		Levels: map[string]uint64{
+			"emerg":   0,
+			"alert":   0,
+			"crit":    1,
+			"err":     2,
+			"warning": 0,
+			"notice":  0,
+			"info":    3,
+			"debug":   0,
+		},
+		Sinks: map[string]SinkMetrics{
+			"syslog": {Success: 2, Failure: 1},
+			"local":  {Success: 3, Failure: 1},
+			"broken": {Success: 0, Failure: 1},
+		},
+		Dropped: 2,
+		Dials:   2,
+	}
+
+	if m := core.Metrics(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("mismatch results. Expecting: “%#v”; found “%#v”", expected, m)
+	}
+
+	core.ResetMetrics()
+	if m := core.Metrics(); m.Levels["err"] != 0 || len(m.Sinks) != 0 {
+		t.Errorf("metrics not reset: “%#v”", m)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	ResetMetrics()
+	defer ResetMetrics()
+
+	std.counters.countLevel(LevelError)
+	std.counters.countSink(sinkSyslog, nil)
+	std.counters.countSink(sinkSyslog, fmt.Errorf("error detected"))
+	std.counters.countDropped()
+
+	w := httptest.NewRecorder()
+	PrometheusHandler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	for _, expected := range []string{
+		`gostk_log_entries_total{level="err"} 1`,
+		`gostk_log_entries_total{level="debug"} 0`,
+		`gostk_log_sink_writes_total{sink="syslog",result="success"} 1`,
+		`gostk_log_sink_writes_total{sink="syslog",result="failure"} 1`,
+		`gostk_log_dropped_total 1`,
+		`gostk_log_dials_total 0`,
+	} {
+		if !strings.Contains(w.Body.String(), expected+"\n") {
+			t.Errorf("metric “%s” not found in “%s”", expected, w.Body.String())
+		}
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failure")
+}
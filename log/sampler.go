@@ -0,0 +1,120 @@
+package log
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Sampler decides if a low priority entry is logged, reducing the log volume
+// under high load. It is checked before the message is formatted, so the
+// entries ignored by the sampler have almost no cost.
+type Sampler interface {
+	Sample(level Level, identifier string) bool
+}
+
+// RateSampler keeps only a fraction of the entries with the given level or
+// lower priorities, while the higher priorities are always logged. A rate
+// doesn't bound the volume under a burst, use a LimitSampler for that. For
+// example, to keep 10% of the info and debug entries grouped by request:
+//
+//    core.SetSampler(log.RateSampler{
+//      Level:        log.LevelInfo,
+//      Rate:         0.1,
+//      ByIdentifier: true,
+//    })
+type RateSampler struct {
+	// Level is the highest priority sampled.
+	Level Level
+
+	// Rate is the fraction of the entries kept, from 0 (none) to 1 (all).
+	Rate float64
+
+	// ByIdentifier makes the decision deterministic using a hash of the logger
+	// identifier, so all the entries of a sampled request are kept together.
+	// Entries without identifier are sampled randomly.
+	ByIdentifier bool
+}
+
+// Sample checks if the entry should be logged.
+func (r RateSampler) Sample(level Level, identifier string) bool {
+	if level < r.Level || r.Rate >= 1 {
+		return true
+	}
+
+	if r.Rate <= 0 {
+		return false
+	}
+
+	if r.ByIdentifier && identifier != "" {
+		hash := fnv.New64a()
+		hash.Write([]byte(identifier))
+		return float64(hash.Sum64()) < r.Rate*math.MaxUint64
+	}
+
+	return rand.Float64() < r.Rate
+}
+
+// LimitSampler bounds the number of entries with the given level or lower
+// priorities logged on each interval, so the log volume is bounded even under a
+// burst, while the higher priorities are always logged. It can be combined with
+// another sampler, checked first. For example, to keep 10% of the info and
+// debug entries, up to 100 entries per second:
+//
+//    core.SetSampler(log.NewLimitSampler(log.LevelInfo, 100, time.Second,
+//      log.RateSampler{Level: log.LevelInfo, Rate: 0.1}))
+//
+// It is safe for concurrent use.
+type LimitSampler struct {
+	level    Level
+	max      int
+	interval time.Duration
+	next     Sampler
+
+	// now is replaced in the tests.
+	now func() time.Time
+
+	mutex sync.Mutex
+	start time.Time
+	count int
+}
+
+// NewLimitSampler returns a sampler that keeps up to max entries of the level
+// or lower priorities on each interval. The next sampler is optional.
+func NewLimitSampler(level Level, max int, interval time.Duration, next Sampler) *LimitSampler {
+	return &LimitSampler{
+		level:    level,
+		max:      max,
+		interval: interval,
+		next:     next,
+		now:      time.Now,
+	}
+}
+
+// Sample checks if the entry should be logged.
+func (l *LimitSampler) Sample(level Level, identifier string) bool {
+	if l.next != nil && !l.next.Sample(level, identifier) {
+		return false
+	}
+
+	if level < l.level {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now := l.now(); now.Sub(l.start) >= l.interval {
+		l.start = now
+		l.count = 0
+	}
+
+	if l.count >= l.max {
+		return false
+	}
+
+	l.count++
+	return true
+}
@@ -0,0 +1,149 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateSampler_Sample(t *testing.T) {
+	scenarios := []struct {
+		description string
+		sampler     RateSampler
+		level       Level
+		identifier  string
+		expected    bool
+	}{
+		{
+			description: "it should always keep higher priority entries",
+			sampler:     RateSampler{Level: LevelInfo},
+			level:       LevelNotice,
+			expected:    true,
+		},
+		{
+			description: "it should drop all entries with a zero rate",
+			sampler:     RateSampler{Level: LevelInfo},
+			level:       LevelDebug,
+			expected:    false,
+		},
+		{
+			description: "it should keep all entries with a full rate",
+			sampler:     RateSampler{Level: LevelInfo, Rate: 1},
+			level:       LevelInfo,
+			identifier:  "request",
+			expected:    true,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if result := scenario.sampler.Sample(scenario.level, scenario.identifier); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestLimitSampler_Sample(t *testing.T) {
+	now := time.Date(2017, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	sampler := NewLimitSampler(LevelInfo, 2, time.Second, RateSampler{Level: LevelDebug})
+	sampler.now = func() time.Time { return now }
+
+	scenarios := []struct {
+		description string
+		level       Level
+		elapsed     time.Duration
+		expected    bool
+	}{
+		{
+			description: "it should keep the first entry of the interval",
+			level:       LevelInfo,
+			expected:    true,
+		},
+		{
+			description: "it should drop the entries rejected by the next sampler",
+			level:       LevelDebug,
+			expected:    false,
+		},
+		{
+			description: "it should keep the entries up to the limit",
+			level:       LevelInfo,
+			elapsed:     500 * time.Millisecond,
+			expected:    true,
+		},
+		{
+			description: "it should drop the entries over the limit",
+			level:       LevelInfo,
+			expected:    false,
+		},
+		{
+			description: "it should always keep higher priority entries",
+			level:       LevelNotice,
+			expected:    true,
+		},
+		{
+			description: "it should keep entries again in the next interval",
+			level:       LevelInfo,
+			elapsed:     500 * time.Millisecond,
+			expected:    true,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		now = now.Add(scenario.elapsed)
+
+		if result := sampler.Sample(scenario.level, "test"); result != scenario.expected {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expected, result)
+		}
+	}
+}
+
+func TestRateSampler_byIdentifier(t *testing.T) {
+	sampler := RateSampler{Level: LevelInfo, Rate: 0.25, ByIdentifier: true}
+
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprintf("request-%d", i)
+
+		decision := sampler.Sample(LevelInfo, id)
+		for j := 0; j < 3; j++ {
+			if sampler.Sample(LevelDebug, id) != decision {
+				t.Fatalf("inconsistent decision for identifier “%s”", id)
+			}
+		}
+
+		if decision {
+			kept++
+		}
+	}
+
+	if kept < 2250 || kept > 2750 {
+		t.Errorf("unexpected sampled volume. Expecting around 2500; found %d", kept)
+	}
+}
+
+func TestCore_SetSampler(t *testing.T) {
+	var localBuffer bytes.Buffer
+	core := NewCore()
+	core.SetLocalLogger(log.New(&localBuffer, "", 0))
+	core.SetLocalEncoder(TextEncoder{})
+	core.SetSampler(RateSampler{Level: LevelInfo})
+
+	l := core.NewLogger("test")
+	l.Info("info message")
+	l.Debugf("debug %s", "message")
+	l.Notice("notice message")
+
+	if result := strings.TrimSpace(localBuffer.String()); !strings.HasSuffix(result, "notice message") ||
+		strings.Contains(result, "\n") {
+		t.Errorf("mismatch results. Expecting only the notice message; found “%s”", result)
+	}
+
+	if m := core.Metrics(); m.Sampled != 2 {
+		t.Errorf("mismatch sampled metric. Expecting: 2; found %d", m.Sampled)
+	}
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSinkClosed returned when writing to a sink that was already closed.
+var ErrSinkClosed = errors.New("sink closed")
+
+// Sink is an extra destination for the log entries, that receives every entry
+// besides the syslog server or the local logger.
+type Sink interface {
+	// Write sends the entry to the destination. Sinks that filter entries
+	// should silently ignore the unwanted ones.
+	Write(e Entry) error
+
+	// Close flushes any pending entry and releases the resources of the sink.
+	Close() error
+}
+
+// Flusher is implemented by sinks that buffer entries, like WebhookSink.
+type Flusher interface {
+	Flush() error
+}
+
+// coreBinder is implemented by sinks that report to the Core where they are
+// added, like WebhookSink.
+type coreBinder interface {
+	bindCore(c *Core)
+}
+
+// AddSink registers an extra destination in the default Core. The name
+// identifies the sink in the metrics and replaces any sink previously
+// registered with the same name.
+func AddSink(name string, s Sink) {
+	std.AddSink(name, s)
+}
+
+// RemoveSink unregisters the destination identified by name from the default
+// Core, returning it so the caller can close it. If there's no sink with the
+// name nil is returned.
+func RemoveSink(name string) Sink {
+	return std.RemoveSink(name)
+}
+
+// Flush sends the buffered entries of all sinks registered in the default
+// Core, returning the first error found.
+func Flush() error {
+	return std.Flush()
+}
+
+// Shutdown flushes and closes all sinks registered in the default Core and the
+// connection with the syslog server, waiting until the context is done. It
+// returns the first error found or the context error when the deadline is
+// exceeded.
+func Shutdown(ctx context.Context) error {
+	return std.Shutdown(ctx)
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func TestAddSink(t *testing.T) {
+	originalRemoteLogger := std.syslog
+	defer func() {
+		std.syslog = originalRemoteLogger
+	}()
+
+	std.syslog = nil
+	LocalLogger = log.New(io.Discard, "", 0)
+
+	var first, second, replaced []string
+	AddSink("first", mockSink{entries: &first})
+	AddSink("second", mockSink{entries: &replaced})
+	AddSink("second", mockSink{entries: &second, err: fmt.Errorf("error detected")})
+
+	ResetMetrics()
+	defer ResetMetrics()
+
+	Info("message 1")
+
+	if s := RemoveSink("first"); s == nil {
+		t.Error("sink “first” not found")
+	}
+	if s := RemoveSink("unknown"); s != nil {
+		t.Errorf("unexpected sink “%v”", s)
+	}
+
+	Info("message 2")
+	RemoveSink("second")
+
+	if expected := []string{"message 1"}; !reflect.DeepEqual(first, expected) {
+		t.Errorf("mismatch entries. Expecting: “%v”; found “%v”", expected, first)
+	}
+
+	if expected := []string{"message 1", "message 2"}; !reflect.DeepEqual(second, expected) {
+		t.Errorf("mismatch entries. Expecting: “%v”; found “%v”", expected, second)
+	}
+
+	if len(replaced) > 0 {
+		t.Errorf("replaced sink received entries “%v”", replaced)
+	}
+
+	if m := CurrentMetrics().Sinks["second"]; m.Failure != 2 {
+		t.Errorf("mismatch sink failures. Expecting: 2; found %d", m.Failure)
+	}
+}
+
+type mockSink struct {
+	name    string
+	entries *[]string
+	closed  *[]string
+	err     error
+}
+
+func (m mockSink) Write(e Entry) error {
+	*m.entries = append(*m.entries, e.Message)
+	return m.err
+}
+
+func (m mockSink) Close() error {
+	*m.closed = append(*m.closed, m.name)
+	return m.err
+}
+
+func TestShutdown(t *testing.T) {
+	originalRemoteLogger := std.syslog
+	defer func() {
+		std.syslog = originalRemoteLogger
+	}()
+
+	var closed []string
+	std.syslog = mockSyslogWriter{
+		mockClose: func() error {
+			closed = append(closed, "syslog")
+			return nil
+		},
+	}
+
+	AddSink("first", mockSink{closed: &closed, name: "first"})
+	AddSink("second", mockSink{closed: &closed, name: "second", err: fmt.Errorf("error detected")})
+
+	if err := Shutdown(context.Background()); !reflect.DeepEqual(err, fmt.Errorf("error detected")) {
+		t.Errorf("unexpected error “%v”", err)
+	}
+
+	if expected := []string{"first", "second", "syslog"}; !reflect.DeepEqual(closed, expected) {
+		t.Errorf("mismatch closed destinations. Expecting: “%v”; found “%v”", expected, closed)
+	}
+
+	if s := RemoveSink("first"); s != nil {
+		t.Error("sink still registered after shutdown")
+	}
+
+	closed = nil
+	AddSink("first", mockSink{closed: &closed, name: "first"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := Shutdown(ctx); err != context.Canceled {
+		t.Errorf("mismatch errors. Expecting: “%v”; found “%v”", context.Canceled, err)
+	}
+
+	if expected := []string{"first"}; !reflect.DeepEqual(closed, expected) {
+		t.Errorf("mismatch closed destinations after the deadline. Expecting: “%v”; found “%v”", expected, closed)
+	}
+}
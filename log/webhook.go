@@ -0,0 +1,275 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// WebhookConfig stores the parameters of a webhook sink. If you are looking
+// for default values see NewWebhookConfig function.
+type WebhookConfig struct {
+	// URL is the HTTP endpoint that receives the batches of entries.
+	URL string
+
+	// Level is the lowest priority sent to the webhook. For example, with
+	// LevelAlert only alert and emergency entries are sent.
+	Level Level
+
+	// Timeout is the maximum wait for each HTTP request.
+	Timeout time.Duration
+
+	// Retries is the number of extra attempts when the request fails or the
+	// endpoint answers with a 429 or 5xx status code.
+	Retries int
+
+	// RetryInterval is the wait before the first retry, being doubled on every
+	// new attempt.
+	RetryInterval time.Duration
+
+	// BatchWindow is how long the entries are accumulated before being sent
+	// together in a single request.
+	BatchWindow time.Duration
+
+	// MaxBatchSize sends the batch immediately when it reaches this number of
+	// entries.
+	MaxBatchSize int
+
+	// Template is a text/template used to build the request body, useful for
+	// Slack or Mattermost style webhooks. The template receives a value with
+	// the Entries field and has the json function to escape values:
+	//
+	//    {"text": {{range .Entries}}{{json .Message}}{{end}}}
+	//
+	// When empty the body is a JSON object with the list of entries.
+	Template string
+
+	// ContentType of the request body.
+	ContentType string
+}
+
+// NewWebhookConfig returns the webhook parameters with some default values,
+// sending only emergency and alert entries.
+func NewWebhookConfig(url string) WebhookConfig {
+	return WebhookConfig{
+		URL:           url,
+		Level:         LevelAlert,
+		Timeout:       5 * time.Second,
+		Retries:       3,
+		RetryInterval: time.Second,
+		BatchWindow:   5 * time.Second,
+		MaxBatchSize:  100,
+		ContentType:   "application/json",
+	}
+}
+
+// WebhookSink is a Sink that POSTs batches of high severity entries to an HTTP
+// endpoint, useful to page someone when the system is in trouble. Entries that
+// couldn't be delivered are written to the local logger and counted as dropped
+// in the metrics of the Core where the sink was added (the default Core when it
+// wasn't added to any).
+type WebhookSink struct {
+	config   WebhookConfig
+	template *template.Template
+	client   *http.Client
+
+	// core is defined when the sink is added to a Core.
+	core *Core
+
+	mutex   sync.Mutex
+	pending []Entry
+	timer   *time.Timer
+	closed  bool
+	sending sync.WaitGroup
+}
+
+// webhookPayload is the data available to the body template.
+type webhookPayload struct {
+	Entries []Entry `json:"entries"`
+}
+
+// NewWebhookSink builds a webhook sink, returning an error if the body
+// template is invalid.
+func NewWebhookSink(config WebhookConfig) (*WebhookSink, error) {
+	w := &WebhookSink{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+
+	if config.Template != "" {
+		funcs := template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				data, err := json.Marshal(v)
+				return string(data), err
+			},
+		}
+
+		var err error
+		if w.template, err = template.New("webhook").Funcs(funcs).Parse(config.Template); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// Write adds the entry to the current batch when its level is high enough.
+func (w *WebhookSink) Write(e Entry) error {
+	if e.Level > w.config.Level {
+		return nil
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return ErrSinkClosed
+	}
+
+	w.pending = append(w.pending, e)
+
+	if w.config.MaxBatchSize > 0 && len(w.pending) >= w.config.MaxBatchSize {
+		w.sendPending()
+	} else if w.timer == nil {
+		w.timer = time.AfterFunc(w.config.BatchWindow, func() {
+			w.mutex.Lock()
+			defer w.mutex.Unlock()
+			w.sendPending()
+		})
+	}
+
+	return nil
+}
+
+// Flush sends the current batch, waiting until all the requests finish.
+func (w *WebhookSink) Flush() error {
+	w.mutex.Lock()
+	w.sendPending()
+	w.mutex.Unlock()
+
+	w.sending.Wait()
+	return nil
+}
+
+// Close sends the current batch and rejects new entries.
+func (w *WebhookSink) Close() error {
+	w.mutex.Lock()
+	w.closed = true
+	w.mutex.Unlock()
+
+	return w.Flush()
+}
+
+// sendPending starts sending the current batch in background. It must be
+// called with the mutex locked.
+func (w *WebhookSink) sendPending() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+
+	if len(w.pending) == 0 {
+		return
+	}
+
+	entries := w.pending
+	w.pending = nil
+
+	w.sending.Add(1)
+	go func() {
+		defer w.sending.Done()
+
+		if err := w.send(entries); err != nil {
+			core := w.owner()
+			local := core.localLogger()
+
+			local.Println("Error sending entries to webhook. Details:", err)
+			for _, e := range entries {
+				core.counters.countDropped()
+				local.Println(encode(TextEncoder{}, e))
+			}
+		}
+	}()
+}
+
+// bindCore stores the Core where the sink was added.
+func (w *WebhookSink) bindCore(c *Core) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.core = c
+}
+
+// owner returns the Core that receives the metrics and the entries that
+// couldn't be delivered.
+func (w *WebhookSink) owner() *Core {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.core == nil {
+		return std
+	}
+	return w.core
+}
+
+// send POSTs the entries, retrying when the endpoint isn't available.
+func (w *WebhookSink) send(entries []Entry) error {
+	body, err := w.body(entries)
+	if err != nil {
+		return err
+	}
+
+	interval := w.config.RetryInterval
+	for attempt := 0; ; attempt++ {
+		err = w.post(body)
+		if err == nil || attempt >= w.config.Retries {
+			return err
+		}
+
+		if _, retry := err.(webhookRetryError); !retry {
+			return err
+		}
+
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+func (w *WebhookSink) body(entries []Entry) ([]byte, error) {
+	payload := webhookPayload{Entries: entries}
+
+	if w.template == nil {
+		return json.Marshal(payload)
+	}
+
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, payload); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *WebhookSink) post(body []byte) error {
+	resp, err := w.client.Post(w.config.URL, w.config.ContentType, bytes.NewReader(body))
+	if err != nil {
+		return webhookRetryError{err}
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return webhookRetryError{fmt.Errorf("unexpected status code %d", resp.StatusCode)}
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// webhookRetryError identifies temporary failures that are worth retrying.
+type webhookRetryError struct {
+	error
+}
@@ -0,0 +1,258 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookSink(t *testing.T) {
+	scenarios := []struct {
+		description    string
+		template       string
+		statusCodes    []int
+		entries        []Entry
+		expectedBodies []string
+		expectedLocal  bool
+	}{
+		{
+			description: "it should send a batch with the high severity entries",
+			entries: []Entry{
+				{Level: LevelEmergency, Identifier: "test", Message: "message 1"},
+				{Level: LevelError, Identifier: "test", Message: "message 2"},
+				{Level: LevelAlert, Identifier: "test", Message: "message 3", Fields: Fields{"zone": "br"}},
+			},
+			expectedBodies: []string{
+				`{"entries":[` +
+					`{"time":"0001-01-01T00:00:00Z","level":"emerg","id":"test","message":"message 1"},` +
+					`{"time":"0001-01-01T00:00:00Z","level":"alert","id":"test","message":"message 3","fields":{"zone":"br"}}` +
+					`]}`,
+			},
+		},
+		{
+			description: "it should use a template to build the body",
+			template:    `{"text": {{range .Entries}}{{json .Message}}{{end}}}`,
+			entries: []Entry{
+				{Level: LevelAlert, Message: `a "quoted" message`},
+			},
+			expectedBodies: []string{
+				`{"text": "a \"quoted\" message"}`,
+			},
+		},
+		{
+			description: "it should retry when the endpoint is unavailable",
+			template:    `{{range .Entries}}{{.Message}}{{end}}`,
+			statusCodes: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			entries: []Entry{
+				{Level: LevelAlert, Message: "message"},
+			},
+			expectedBodies: []string{"message", "message", "message"},
+		},
+		{
+			description: "it should not retry when the request is rejected",
+			template:    `{{range .Entries}}{{.Message}}{{end}}`,
+			statusCodes: []int{http.StatusBadRequest},
+			entries: []Entry{
+				{Level: LevelAlert, Message: "message"},
+			},
+			expectedBodies: []string{"message"},
+			expectedLocal:  true,
+		},
+		{
+			description: "it should give up after all the retries",
+			template:    `{{range .Entries}}{{.Message}}{{end}}`,
+			statusCodes: []int{500, 500, 500, 500},
+			entries: []Entry{
+				{Level: LevelAlert, Message: "message"},
+			},
+			expectedBodies: []string{"message", "message", "message"},
+			expectedLocal:  true,
+		},
+	}
+
+	var localBuffer bytes.Buffer
+	LocalLogger = log.New(&localBuffer, "", 0)
+	defer ResetMetrics()
+
+	for i, scenario := range scenarios {
+		localBuffer.Reset()
+		ResetMetrics()
+
+		var mutex sync.Mutex
+		var bodies []string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+
+			if len(scenario.statusCodes) >= len(bodies) {
+				w.WriteHeader(scenario.statusCodes[len(bodies)-1])
+			}
+		}))
+
+		config := NewWebhookConfig(server.URL)
+		config.Template = scenario.template
+		config.Retries = 2
+		config.RetryInterval = time.Millisecond
+		config.BatchWindow = time.Hour
+
+		sink, err := NewWebhookSink(config)
+		if err != nil {
+			t.Fatalf("scenario %d, “%s”: unexpected error: %s", i, scenario.description, err)
+		}
+
+		for _, e := range scenario.entries {
+			if err := sink.Write(e); err != nil {
+				t.Errorf("scenario %d, “%s”: unexpected error: %s", i, scenario.description, err)
+			}
+		}
+
+		if err := sink.Close(); err != nil {
+			t.Errorf("scenario %d, “%s”: unexpected error: %s", i, scenario.description, err)
+		}
+		server.Close()
+
+		if len(bodies) != len(scenario.expectedBodies) {
+			t.Errorf("scenario %d, “%s”: mismatch requests. Expecting: “%v”; found “%v”",
+				i, scenario.description, scenario.expectedBodies, bodies)
+			continue
+		}
+
+		for j := range bodies {
+			if bodies[j] != scenario.expectedBodies[j] {
+				t.Errorf("scenario %d, “%s”: mismatch body. Expecting: “%s”; found “%s”",
+					i, scenario.description, scenario.expectedBodies[j], bodies[j])
+			}
+		}
+
+		if scenario.expectedLocal != (localBuffer.Len() > 0) {
+			t.Errorf("scenario %d, “%s”: unexpected local output “%s”",
+				i, scenario.description, localBuffer.String())
+		}
+
+		var expectedDropped uint64
+		if scenario.expectedLocal {
+			expectedDropped = uint64(len(scenario.entries))
+		}
+
+		if dropped := CurrentMetrics().Dropped; dropped != expectedDropped {
+			t.Errorf("scenario %d, “%s”: mismatch dropped entries. Expecting: %d; found %d",
+				i, scenario.description, expectedDropped, dropped)
+		}
+	}
+}
+
+func TestWebhookSink_batchWindow(t *testing.T) {
+	type payload struct {
+		Entries []map[string]interface{} `json:"entries"`
+	}
+	requests := make(chan payload, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+		requests <- p
+	}))
+	defer server.Close()
+
+	config := NewWebhookConfig(server.URL)
+	config.BatchWindow = 10 * time.Millisecond
+	config.MaxBatchSize = 3
+
+	sink, err := NewWebhookSink(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	AddSink("webhook", sink)
+	defer RemoveSink("webhook")
+
+	LocalLogger = log.New(io.Discard, "", 0)
+	originalRemoteLogger := std.syslog
+	std.syslog = nil
+	defer func() {
+		std.syslog = originalRemoteLogger
+	}()
+
+	for i := 0; i < 4; i++ {
+		Alert("this is a message")
+	}
+
+	for _, expected := range []int{3, 1} {
+		select {
+		case p := <-requests:
+			if len(p.Entries) != expected {
+				t.Errorf("mismatch batch size. Expecting: %d; found: %d", expected, len(p.Entries))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the webhook request")
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Write(Entry{Level: LevelAlert}); err != ErrSinkClosed {
+		t.Errorf("mismatch error. Expecting: “%v”; found “%v”", ErrSinkClosed, err)
+	}
+}
+
+func TestWebhookSink_core(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	ResetMetrics()
+	defer ResetMetrics()
+
+	var defaultBuffer, coreBuffer bytes.Buffer
+	LocalLogger = log.New(&defaultBuffer, "", 0)
+
+	core := NewCore()
+	core.SetLocalLogger(log.New(&coreBuffer, "", 0))
+
+	sink, err := NewWebhookSink(NewWebhookConfig(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.AddSink("webhook", sink)
+
+	if err := sink.Write(Entry{Level: LevelAlert, Identifier: "test", Message: "this is a message"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(coreBuffer.String(), "[test] this is a message") {
+		t.Errorf("entry not written to the core local logger: “%s”", coreBuffer.String())
+	}
+
+	if defaultBuffer.Len() > 0 {
+		t.Errorf("unexpected output in the default local logger: “%s”", defaultBuffer.String())
+	}
+
+	if dropped := core.Metrics().Dropped; dropped != 1 {
+		t.Errorf("mismatch dropped entries. Expecting: 1; found %d", dropped)
+	}
+
+	if dropped := CurrentMetrics().Dropped; dropped != 0 {
+		t.Errorf("dropped entries counted in the default core: %d", dropped)
+	}
+}
@@ -1,21 +1,24 @@
 // Package path implements utility routines for manipulating slash-separated paths.
 package path
 
-import "strings"
-
 // RelevantPath returns the right n directories from the path. If n is bigger
 // than the number of directories the full path is returned.
 func RelevantPath(path string, n int) string {
-	tokens := strings.Split(path, "/")
-	total := len(tokens)
-
-	if n <= 0 || n >= total {
+	if n <= 0 {
 		return path
 	}
 
-	var result string
-	for i := total - n; i < total; i++ {
-		result += tokens[i] + "/"
+	// look for the n-th separator from the right, returning the content after
+	// it without allocating a new string
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] != '/' {
+			continue
+		}
+
+		if n--; n == 0 {
+			return path[i+1:]
+		}
 	}
-	return strings.TrimSuffix(result, "/")
+
+	return path
 }
@@ -25,6 +25,18 @@ func TestRelevantPath(t *testing.T) {
 			n:           5,
 			expected:    "1/2/3/4/5",
 		},
+		{
+			description: "it should remove the extra directories of an absolute path correctly",
+			path:        "/1/2/3",
+			n:           2,
+			expected:    "2/3",
+		},
+		{
+			description: "it should avoid removing directories of an absolute path when n is bigger than the number of directories",
+			path:        "/1/2/3",
+			n:           4,
+			expected:    "/1/2/3",
+		},
 		{
 			description: "it should avoid removing directories when n is zero",
 			path:        "1/2/3/4/5",
@@ -47,3 +59,11 @@ func TestRelevantPath(t *testing.T) {
 		}
 	}
 }
+
+func BenchmarkRelevantPath(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		path.RelevantPath("/home/user/go/src/github.com/registrobr/gostk/log/log.go", 3)
+	}
+}
@@ -0,0 +1,117 @@
+// Package problem converts errors into HTTP responses in the problem details
+// format (RFC 7807), using the codes and categories of the errors package.
+//
+// The response exposes only the error code and the message of coded errors,
+// hiding the locations and the low level errors from the clients, while the
+// full error is logged with the request identifier:
+//
+//    http.Handle("/domains/", problem.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//      domain, err := load(r.URL.Path)
+//      if err != nil {
+//        return err
+//      }
+//      return json.NewEncoder(w).Encode(domain)
+//    }))
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/registrobr/gostk/errors"
+	"github.com/registrobr/gostk/log"
+)
+
+// ContentType is the media type of the problem details responses.
+const ContentType = "application/problem+json"
+
+// RequestIDHeader is the HTTP header with the request identifier, used to tag
+// the log messages and returned to the client in the problem details.
+var RequestIDHeader = "X-Request-Id"
+
+// CategoryStatus maps the error categories to the HTTP status codes. Errors
+// without a known category are reported as internal server errors. It should
+// be modified only during the initialization.
+var CategoryStatus = map[errors.Category]int{
+	errors.CategoryValidation:  http.StatusBadRequest,
+	errors.CategoryNotFound:    http.StatusNotFound,
+	errors.CategoryConflict:    http.StatusConflict,
+	errors.CategoryInternal:    http.StatusInternalServerError,
+	errors.CategoryUnavailable: http.StatusServiceUnavailable,
+}
+
+// CodeStatus maps specific error codes to HTTP status codes, replacing the
+// status of the category. For example, to report an expired session with 401:
+//
+//    problem.CodeStatus["SESSION_EXPIRED"] = http.StatusUnauthorized
+//
+// It should be modified only during the initialization.
+var CodeStatus = map[string]int{}
+
+// Problem is the body of a problem details response. The Code and RequestID
+// members are extensions with the error code and the request identifier.
+type Problem struct {
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Status returns the HTTP status code of the error, checking the code and
+// then the category of the first coded error in the chain.
+func Status(err error) int {
+	if status, ok := CodeStatus[errors.Code(err)]; ok {
+		return status
+	}
+
+	if status, ok := CategoryStatus[errors.CategoryOf(err)]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// New builds the problem details of the error. Only the code and the message
+// of coded errors are exposed; internal errors have only the generic title.
+func New(err error) Problem {
+	status := Status(err)
+
+	return Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: errors.Message(err),
+		Code:   errors.Code(err),
+	}
+}
+
+// Write logs the error, tagged with the request identifier, and sends the
+// problem details response to the client.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	requestID := r.Header.Get(RequestIDHeader)
+	log.NewLogger(requestID).Error(err)
+
+	p := New(err)
+	p.Instance = r.URL.Path
+	p.RequestID = requestID
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.NewLogger(requestID).Warningf("Error writing the problem details. Details: %s", err)
+	}
+}
+
+// HandlerFunc is an HTTP handler that returns an error, converted to a problem
+// details response with Write.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f(w, r), writing the returned error.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		Write(w, r, err)
+	}
+}
@@ -0,0 +1,145 @@
+package problem_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/registrobr/gostk/errors"
+	"github.com/registrobr/gostk/log"
+	"github.com/registrobr/gostk/problem"
+)
+
+var (
+	errDomainNotFound = errors.Define("PROBLEM_DOMAIN_NOT_FOUND", errors.CategoryNotFound, log.LevelNotice, "domain %s not found")
+	errSessionExpired = errors.Define("PROBLEM_SESSION_EXPIRED", errors.CategoryValidation, log.LevelInfo, "session expired")
+	errDatabaseDown   = errors.Define("PROBLEM_DATABASE_DOWN", errors.CategoryUnavailable, log.LevelCritical, "database unavailable")
+)
+
+func TestNew(t *testing.T) {
+	problem.CodeStatus["PROBLEM_SESSION_EXPIRED"] = http.StatusUnauthorized
+	defer delete(problem.CodeStatus, "PROBLEM_SESSION_EXPIRED")
+
+	scenarios := []struct {
+		description string
+		err         error
+		expected    problem.Problem
+	}{
+		{
+			description: "it should map the category to the status",
+			err:         errors.Wrap(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "loading the domain"),
+			expected: problem.Problem{
+				Title:  "Not Found",
+				Status: http.StatusNotFound,
+				Detail: "domain example.com.br not found",
+				Code:   "PROBLEM_DOMAIN_NOT_FOUND",
+			},
+		},
+		{
+			description: "it should map a specific code to the status",
+			err:         errSessionExpired.New(),
+			expected: problem.Problem{
+				Title:  "Unauthorized",
+				Status: http.StatusUnauthorized,
+				Detail: "session expired",
+				Code:   "PROBLEM_SESSION_EXPIRED",
+			},
+		},
+		{
+			description: "it should map an unavailable dependency",
+			err:         errDatabaseDown.Wrap(sql.ErrConnDone),
+			expected: problem.Problem{
+				Title:  "Service Unavailable",
+				Status: http.StatusServiceUnavailable,
+				Detail: "database unavailable",
+				Code:   "PROBLEM_DATABASE_DOWN",
+			},
+		},
+		{
+			description: "it should map the first coded error of an aggregate",
+			err: func() error {
+				var errs errors.MultiError
+				errs.Append(errDomainNotFound.New("a.br"), errDomainNotFound.New("b.br"))
+				return &errs
+			}(),
+			expected: problem.Problem{
+				Title:  "Not Found",
+				Status: http.StatusNotFound,
+				Detail: "domain a.br not found",
+				Code:   "PROBLEM_DOMAIN_NOT_FOUND",
+			},
+		},
+		{
+			description: "it should hide the details of an internal error",
+			err:         errors.Critf("connection to 10.0.0.1 refused"),
+			expected: problem.Problem{
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		if p := problem.New(scenario.err); !reflect.DeepEqual(p, scenario.expected) {
+			t.Errorf("scenario %d, “%s”: mismatch results. Expecting: “%#v”; found “%#v”",
+				i, scenario.description, scenario.expected, p)
+		}
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	var buf bytes.Buffer
+	originalLocalLogger := log.LocalLogger
+	defer func() {
+		log.LocalLogger = originalLocalLogger
+	}()
+	log.LocalLogger = stdlog.New(&buf, "", 0)
+
+	handler := problem.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.Wrap(errDomainNotFound.Wrap(sql.ErrNoRows, "example.com.br"), "loading the domain")
+	})
+
+	r := httptest.NewRequest("GET", "/domains/example.com.br", nil)
+	r.Header.Set("X-Request-Id", "abc123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("mismatch status. Expecting: %d; found %d", http.StatusNotFound, w.Code)
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("mismatch content type. Expecting: “application/problem+json”; found “%s”", contentType)
+	}
+
+	var p problem.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("error decoding the response: %s", err)
+	}
+
+	expected := problem.Problem{
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "domain example.com.br not found",
+		Instance:  "/domains/example.com.br",
+		Code:      "PROBLEM_DOMAIN_NOT_FOUND",
+		RequestID: "abc123",
+	}
+
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("mismatch response. Expecting: “%#v”; found “%#v”", expected, p)
+	}
+
+	expectedLog := regexp.MustCompile(`^\[abc123\] gostk/problem/problem_test.go:[0-9]+: loading the domain → ` +
+		`gostk/problem/problem_test.go:[0-9]+: domain example.com.br not found: sql: no rows in result set\n$`)
+
+	if result := buf.String(); !expectedLog.MatchString(result) {
+		t.Errorf("mismatch log. Expecting: “%s”; found “%s”", expectedLog.String(), result)
+	}
+}
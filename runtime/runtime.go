@@ -0,0 +1,181 @@
+// Package runtime adds some useful features to the standard runtime package.
+package runtime
+
+import (
+	"runtime"
+	"sync"
+)
+
+// maxStackDepth defines the maximum number of stack frames inspected when
+// looking for the first caller that isn't a helper function.
+const maxStackDepth = 32
+
+// callSite identifies a location lookup by the top of the call stack.
+type callSite [4]uintptr
+
+// location is the result of a location lookup.
+type location struct {
+	file string
+	line int
+}
+
+// helpers stores the full name of all functions marked as helpers. The program
+// counters already resolved are also stored, so the function name lookup is
+// done only once for each call site. The callers map caches the results of
+// Caller, as resolving the frames allocates memory, and it is cleared when a
+// new helper is registered.
+var helpers = struct {
+	sync.RWMutex
+	names   map[string]struct{}
+	pcs     map[uintptr]struct{}
+	callers map[callSite]location
+}{
+	names:   make(map[string]struct{}),
+	pcs:     make(map[uintptr]struct{}),
+	callers: make(map[callSite]location),
+}
+
+// Helper marks the calling function as a helper function. When retrieving the
+// location of a log entry or an error, helper functions are skipped, so a
+// wrapper over the log or errors packages reports the location of its own
+// caller. It works like testing.T.Helper and can be called simultaneously from
+// multiple goroutines.
+//
+//    func logFailure(err error) {
+//      runtime.Helper()
+//      log.Errorf("failure detected: %s", err)
+//    }
+func Helper() {
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) == 0 {
+		return
+	}
+
+	helpers.RLock()
+	_, found := helpers.pcs[pc[0]]
+	helpers.RUnlock()
+
+	if found {
+		return
+	}
+
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+
+	helpers.Lock()
+	if _, found := helpers.names[frame.Function]; !found {
+		helpers.names[frame.Function] = struct{}{}
+		helpers.callers = make(map[callSite]location)
+	}
+	helpers.pcs[pc[0]] = struct{}{}
+	helpers.Unlock()
+}
+
+// Caller works like the standard runtime.Caller, reporting the file and line
+// of the function invocation on the calling goroutine's stack, but skipping all
+// functions marked with Helper. The argument skip is the number of stack frames
+// to ascend, with 0 identifying the caller of Caller.
+func Caller(skip int) (file string, line int, ok bool) {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	if n == 0 {
+		return "", 0, false
+	}
+
+	var site callSite
+	limit := copy(site[:], pcs[:n])
+
+	helpers.RLock()
+	cached, found := helpers.callers[site]
+	helpers.RUnlock()
+
+	if found {
+		return cached.file, cached.line, true
+	}
+
+	file, line, found = resolve(pcs[:limit])
+	if !found && n > limit {
+		// there are too many helpers to identify the location only by the
+		// call site, so it isn't cached
+		file, line, _ = resolve(pcs[:n])
+		return file, line, true
+	}
+
+	helpers.Lock()
+	helpers.callers[site] = location{file: file, line: line}
+	helpers.Unlock()
+
+	return file, line, true
+}
+
+// resolve returns the location of the first frame that isn't a helper. When
+// all frames are helpers the last one is returned with found as false.
+func resolve(pcs []uintptr) (file string, line int, found bool) {
+	helpers.RLock()
+	defer helpers.RUnlock()
+
+	// the program counters are copied, so the caller's array doesn't escape to
+	// the heap
+	frames := runtime.CallersFrames(append([]uintptr(nil), pcs...))
+	for {
+		frame, more := frames.Next()
+		file, line = frame.File, frame.Line
+
+		if _, helper := helpers.names[frame.Function]; !helper {
+			return file, line, true
+		}
+
+		if !more {
+			return file, line, false
+		}
+	}
+}
+
+// Frame is a function invocation in a call stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Stack stores the program counters of a call stack, that are resolved to
+// frames only when needed.
+type Stack []uintptr
+
+// Callers returns the call stack of the calling goroutine. The functions marked
+// with Helper on the top of the stack are removed when retrieving the frames.
+// The argument skip is the number of stack frames to ascend, with 0
+// identifying the caller of Callers.
+func Callers(skip int) Stack {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return append(Stack(nil), pcs[:n]...)
+}
+
+// Frames resolves the program counters of the stack, ignoring the helper
+// functions on the top.
+func (s Stack) Frames() []Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	helpers.RLock()
+	defer helpers.RUnlock()
+
+	var frames []Frame
+	callersFrames := runtime.CallersFrames(s)
+	for {
+		frame, more := callersFrames.Next()
+
+		if _, helper := helpers.names[frame.Function]; !helper || len(frames) > 0 || !more {
+			frames = append(frames, Frame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+
+		if !more {
+			return frames
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package runtime_test
+
+import (
+	"regexp"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/registrobr/gostk/runtime"
+)
+
+func TestCaller(t *testing.T) {
+	scenarios := []struct {
+		description string
+		caller      func() (file string, line int, ok bool, expectedLine int)
+	}{
+		{
+			description: "it should retrieve the caller correctly",
+			caller: func() (string, int, bool, int) {
+				_, _, expectedLine, _ := goruntime.Caller(0)
+				file, line, ok := runtime.Caller(0)
+				return file, line, ok, expectedLine + 1
+			},
+		},
+		{
+			description: "it should skip the helper functions",
+			caller: func() (string, int, bool, int) {
+				_, _, expectedLine, _ := goruntime.Caller(0)
+				file, line, ok := helperCaller()
+				return file, line, ok, expectedLine + 1
+			},
+		},
+		{
+			description: "it should skip nested helper functions",
+			caller: func() (string, int, bool, int) {
+				_, _, expectedLine, _ := goruntime.Caller(0)
+				file, line, ok := nestedHelperCaller()
+				return file, line, ok, expectedLine + 1
+			},
+		},
+		{
+			description: "it should not skip functions that aren't helpers",
+			caller: func() (string, int, bool, int) {
+				return notHelperCaller()
+			},
+		},
+	}
+
+	for i, scenario := range scenarios {
+		file, line, ok, expectedLine := scenario.caller()
+		if !ok {
+			t.Errorf("scenario %d, “%s”: caller not found", i, scenario.description)
+			continue
+		}
+
+		if !strings.HasSuffix(file, "/runtime/runtime_test.go") || line != expectedLine {
+			t.Errorf("scenario %d, “%s”: mismatch location. Expecting: “runtime/runtime_test.go:%d”; found “%s:%d”",
+				i, scenario.description, expectedLine, file, line)
+		}
+	}
+}
+
+func helperCaller() (string, int, bool) {
+	runtime.Helper()
+	return runtime.Caller(0)
+}
+
+func nestedHelperCaller() (string, int, bool) {
+	runtime.Helper()
+	return helperCaller()
+}
+
+func notHelperCaller() (file string, line int, ok bool, expectedLine int) {
+	_, _, expectedLine, _ = goruntime.Caller(0)
+	file, line, ok = helperCaller()
+	return file, line, ok, expectedLine + 1
+}
+
+func TestCallers(t *testing.T) {
+	_, _, line, _ := goruntime.Caller(0)
+	frames := helperCallers().Frames()
+	if len(frames) < 2 {
+		t.Fatalf("missing frames: %v", frames)
+	}
+
+	expected := regexp.MustCompile(`^github.com/registrobr/gostk/runtime_test.TestCallers /.*/runtime/runtime_test.go:` + strconv.Itoa(line+1) + `$`)
+	if result := frames[0].Function + " " + frames[0].File + ":" + strconv.Itoa(frames[0].Line); !expected.MatchString(result) {
+		t.Errorf("mismatch results. Expecting: “%v”; found “%v”", expected.String(), result)
+	}
+
+	if result := frames[1].Function; result != "testing.tRunner" {
+		t.Errorf("mismatch results. Expecting: “testing.tRunner”; found “%v”", result)
+	}
+}
+
+func helperCallers() runtime.Stack {
+	runtime.Helper()
+	return runtime.Callers(0)
+}